@@ -1,75 +1,51 @@
 package malgomate
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
 )
 
-// RankingPage is a paginated response page for ranking query results
-type RankingPage struct {
-	Ranking []Ranking `json:"data"`
-	Paging  Paging    `json:"paging"`
-}
-
-// JSON is a helper function converts a RankingPage to a JSON string
-func (rp *RankingPage) JSON() (string, error) {
-	if s, err := json.MarshalIndent(rp, "", "  "); err == nil {
-		return string(s), err
-	} else {
-		return "", err
-	}
-}
-
-// ListPage is a paginated response page for a query that returns a list of results
-type ListPage struct {
-	Listing []Listing `json:"data"`
-	Paging  Paging    `json:"paging"`
-}
-
-// JSON is a helper function converts a ListPage to a JSON string
-func (lp *ListPage) JSON() (string, error) {
-	if s, err := json.MarshalIndent(lp, "", "  "); err == nil {
-		return string(s), err
-	} else {
-		return "", err
-	}
-}
-
 // Anime are the general response objects
 type Anime struct {
 	ID                     int                `json:"id"`
 	Title                  string             `json:"title"`
 	MainPicture            *MainPicture       `json:"main_picture,omitempty"`
 	AlternativeTitles      *AlternativeTitles `json:"alternative_titles,omitempty"`
-	StartDate              string             `json:"start_date,omitempty"`
-	EndDate                string             `json:"end_date,omitempty"`
+	StartDate              *PartialDate       `json:"start_date,omitempty"`
+	EndDate                *PartialDate       `json:"end_date,omitempty"`
 	Synopsis               string             `json:"synopsis,omitempty"`
 	Mean                   float64            `json:"mean,omitempty"`
 	Rank                   int                `json:"rank,omitempty"`
 	Popularity             int                `json:"popularity,omitempty"`
 	NumListUsers           int                `json:"num_list_users,omitempty"`
 	NumScoringUsers        int                `json:"num_scoring_users,omitempty"`
-	Nsfw                   string             `json:"nsfw,omitempty"`
-	CreatedAt              string             `json:"created_at,omitempty"`
-	UpdatedAt              string             `json:"updated_at,omitempty"`
-	MediaType              string             `json:"media_type,omitempty"`
-	Status                 string             `json:"status,omitempty"`
+	Nsfw                   Nsfw               `json:"nsfw,omitempty"`
+	CreatedAt              *Timestamp         `json:"created_at,omitempty"`
+	UpdatedAt              *Timestamp         `json:"updated_at,omitempty"`
+	MediaType              MediaType          `json:"media_type,omitempty"`
+	Status                 AiringStatus       `json:"status,omitempty"`
 	Genres                 []*Genres          `json:"genres,omitempty"`
 	NumEpisodes            int                `json:"num_episodes,omitempty"`
 	StartSeason            *StartSeason       `json:"start_season,omitempty"`
 	Broadcast              *Broadcast         `json:"broadcast,omitempty"`
-	Source                 string             `json:"source,omitempty"`
+	Source                 SourceType         `json:"source,omitempty"`
 	AverageEpisodeDuration int                `json:"average_episode_duration,omitempty"`
-	Rating                 string             `json:"rating,omitempty"`
+	Rating                 Rating             `json:"rating,omitempty"`
 	Pictures               []*Pictures        `json:"pictures,omitempty"`
 	Background             string             `json:"background,omitempty"`
 	RelatedAnime           []*RelatedAnime    `json:"related_anime,omitempty"`
-	RelatedManga           []interface{}      `json:"related_manga,omitempty"`
+	RelatedManga           []*RelatedManga    `json:"related_manga,omitempty"`
 	Recommendations        []*Recommendations `json:"recommendations,omitempty"`
 	Studios                []*Studios         `json:"studios,omitempty"`
 	Statistics             *Statistics        `json:"statistics,omitempty"`
+	MyListStatus           *ListStatus        `json:"my_list_status,omitempty"`
+
+	// present and requested track the fields that were in the response, and that were asked for
+	present   map[Field]bool
+	requested Fields
 }
 
 // JSON is a helper function that converts an anime object to a JSON string
@@ -211,8 +187,37 @@ type SeasonalQuery struct {
 	Fields QueryFields
 }
 
+// SuggestionsQuery is used to query for anime suggested to the authenticated user. Supports fields of the
+// QueryField type
+type SuggestionsQuery struct {
+	Limit  int
+	Offset int
+	Fields QueryFields
+}
+
 // GetDetails retrieves specifics for a given MAL anime Id.
 func (c *Client) GetDetails(dq *DetailsQuery) (*Anime, error) {
+	return c.GetDetailsContext(context.Background(), dq)
+}
+
+// GetDetailsContext is the same as GetDetails, but the request is bound to the provided context. Cancelling the
+// context aborts the request.
+func (c *Client) GetDetailsContext(ctx context.Context, dq *DetailsQuery) (*Anime, error) {
+	req, err := c.detailsRequest(ctx, dq)
+	if err != nil {
+		return nil, err
+	}
+
+	res := Anime{}
+	if err := c.sendRequest(req, &res); err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+// detailsRequest builds the request for a details query, filling in any defaults and checking the query is valid
+func (c *Client) detailsRequest(ctx context.Context, dq *DetailsQuery) (*http.Request, error) {
 	// Check for required values
 	if dq.Id == 0 {
 		return nil, errors.New("missing required parameter: Id must be set")
@@ -222,29 +227,43 @@ func (c *Client) GetDetails(dq *DetailsQuery) (*Anime, error) {
 		dq.Fields = BasicDetailQuery
 	}
 
+	if err := dq.Fields.validate(EndpointDetails, mediaAnime); err != nil {
+		return nil, err
+	}
+
 	queryFields := dq.Fields.ToString()
 	queryString := fmt.Sprintf("%s/anime/%d?fields=%s", c.BaseURL, dq.Id, queryFields)
-	req, err := http.NewRequest(http.MethodGet, queryString, nil)
+	return http.NewRequestWithContext(ctx, http.MethodGet, queryString, nil)
+}
+
+// GetAnime queries all anime based on a provided string. These queries return a paged list of responses containing
+// the fields specified in the intial request object. The query string must be provided. If not included, the
+// following default values will be used:
+//    * Limit - 100 (max 100)
+//    * Offset - 0
+//    * Fields - "id,title,main_picture"
+func (c *Client) GetAnime(aq *AnimeQuery) (*ListPage, error) {
+	return c.GetAnimeContext(context.Background(), aq)
+}
+
+// GetAnimeContext is the same as GetAnime, but the request is bound to the provided context. Cancelling the
+// context aborts the request.
+func (c *Client) GetAnimeContext(ctx context.Context, aq *AnimeQuery) (*ListPage, error) {
+	req, err := c.animeRequest(ctx, aq)
 	if err != nil {
 		return nil, err
 	}
 
-	res := Anime{}
+	res := ListPage{}
 	if err := c.sendRequest(req, &res); err != nil {
 		return nil, err
 	}
 
 	return &res, nil
-
 }
 
-// GetAnime queries all anime based on a provided string. These queries return a paged list of responses containing
-// the fields specified in the intial request object. The query string must be provided. If not included, the
-// following default values will be used:
-//    * Limit - 100 (max 100)
-//    * Offset - 0
-//    * Fields - "id,title,main_picture"
-func (c *Client) GetAnime(aq *AnimeQuery) (*ListPage, error) {
+// animeRequest builds the request for an anime query, filling in any defaults and checking the query is valid
+func (c *Client) animeRequest(ctx context.Context, aq *AnimeQuery) (*http.Request, error) {
 	// Check for required values
 	if aq.Query == "" {
 		return nil, errors.New("missing required parameter: Query must be set")
@@ -259,19 +278,13 @@ func (c *Client) GetAnime(aq *AnimeQuery) (*ListPage, error) {
 		aq.Fields = BasicFieldQuery
 	}
 
-	queryFields := aq.Fields.ToString()
-	queryString := fmt.Sprintf("%s/anime?q=%s&limit=%d&offset=%d&fields=%s", c.BaseURL, aq.Query, aq.Limit, aq.Offset, queryFields)
-	req, err := http.NewRequest(http.MethodGet, queryString, nil)
-	if err != nil {
+	if err := aq.Fields.validate(EndpointList, mediaAnime); err != nil {
 		return nil, err
 	}
 
-	res := ListPage{}
-	if err := c.sendRequest(req, &res); err != nil {
-		return nil, err
-	}
-
-	return &res, nil
+	queryFields := aq.Fields.ToString()
+	queryString := fmt.Sprintf("%s/anime?q=%s&limit=%d&offset=%d&fields=%s", c.BaseURL, aq.Query, aq.Limit, aq.Offset, queryFields)
+	return http.NewRequestWithContext(ctx, http.MethodGet, queryString, nil)
 }
 
 // GetRanking queries all anime based on rankings on MAL. These queries return a paged list of responses containing
@@ -282,6 +295,27 @@ func (c *Client) GetAnime(aq *AnimeQuery) (*ListPage, error) {
 //    * Offset - 0
 //    * Fields - "id,title,main_picture"
 func (c *Client) GetRanking(r *RankingQuery) (*RankingPage, error) {
+	return c.GetRankingContext(context.Background(), r)
+}
+
+// GetRankingContext is the same as GetRanking, but the request is bound to the provided context. Cancelling the
+// context aborts the request.
+func (c *Client) GetRankingContext(ctx context.Context, r *RankingQuery) (*RankingPage, error) {
+	req, err := c.rankingRequest(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+
+	res := RankingPage{}
+	if err := c.sendRequest(req, &res); err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+// rankingRequest builds the request for a ranking query, filling in any defaults and checking the query is valid
+func (c *Client) rankingRequest(ctx context.Context, r *RankingQuery) (*http.Request, error) {
 	// Handle defaults
 	if r.RankingType == "" {
 		r.RankingType = RankingAll
@@ -295,19 +329,13 @@ func (c *Client) GetRanking(r *RankingQuery) (*RankingPage, error) {
 		r.Fields = BasicFieldQuery
 	}
 
-	queryFields := r.Fields.ToString()
-	queryString := fmt.Sprintf("%s/anime/ranking?ranking_type=%s&limit=%d&offset=%d&fields=%s", c.BaseURL, r.RankingType, r.Limit, r.Offset, queryFields)
-	req, err := http.NewRequest(http.MethodGet, queryString, nil)
-	if err != nil {
+	if err := r.Fields.validate(EndpointRanking, mediaAnime); err != nil {
 		return nil, err
 	}
 
-	res := RankingPage{}
-	if err := c.sendRequest(req, &res); err != nil {
-		return nil, err
-	}
-
-	return &res, nil
+	queryFields := r.Fields.ToString()
+	queryString := fmt.Sprintf("%s/anime/ranking?ranking_type=%s&limit=%d&offset=%d&fields=%s", c.BaseURL, r.RankingType, r.Limit, r.Offset, queryFields)
+	return http.NewRequestWithContext(ctx, http.MethodGet, queryString, nil)
 }
 
 // GetSeason queries for seasonal anime. These queries return a paged list of responses containing the fields specified
@@ -318,6 +346,27 @@ func (c *Client) GetRanking(r *RankingQuery) (*RankingPage, error) {
 //    * Offset - 0
 //    * Fields - "id,title,main_picture"
 func (c *Client) GetSeason(q *SeasonalQuery) (*ListPage, error) {
+	return c.GetSeasonContext(context.Background(), q)
+}
+
+// GetSeasonContext is the same as GetSeason, but the request is bound to the provided context. Cancelling the
+// context aborts the request.
+func (c *Client) GetSeasonContext(ctx context.Context, q *SeasonalQuery) (*ListPage, error) {
+	req, err := c.seasonRequest(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	res := ListPage{}
+	if err := c.sendRequest(req, &res); err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+// seasonRequest builds the request for a seasonal query, filling in any defaults and checking the query is valid
+func (c *Client) seasonRequest(ctx context.Context, q *SeasonalQuery) (*http.Request, error) {
 	// Check for required values
 	if q.Year == 0 || q.Season == "" {
 		return nil, errors.New("missing required parameter: Year and Season must be set")
@@ -335,10 +384,29 @@ func (c *Client) GetSeason(q *SeasonalQuery) (*ListPage, error) {
 		q.Fields = BasicFieldQuery
 	}
 
-	// Query
+	if err := q.Fields.validate(EndpointSeason, mediaAnime); err != nil {
+		return nil, err
+	}
+
 	queryFields := q.Fields.ToString()
 	queryString := fmt.Sprintf("%s/anime/season/%d/%s?sort=%s&limit=%d&offset=%d&fields=%s", c.BaseURL, q.Year, q.Season, q.Sort, q.Limit, q.Offset, queryFields)
-	req, err := http.NewRequest(http.MethodGet, queryString, nil)
+	return http.NewRequestWithContext(ctx, http.MethodGet, queryString, nil)
+}
+
+// GetSuggestions queries for anime that MAL suggests to the authenticated user. These queries return a paged list
+// of responses containing the fields specified in the initial request object. Requires a client created with
+// NewAuthClient, and returns ErrAuthRequired otherwise. If not included, the following default values will be used:
+//   - Limit - 100 (max 100)
+//   - Offset - 0
+//   - Fields - "id,title,main_picture"
+func (c *Client) GetSuggestions(q *SuggestionsQuery) (*ListPage, error) {
+	return c.GetSuggestionsContext(context.Background(), q)
+}
+
+// GetSuggestionsContext is the same as GetSuggestions, but the request is bound to the provided context. Cancelling
+// the context aborts the request.
+func (c *Client) GetSuggestionsContext(ctx context.Context, q *SuggestionsQuery) (*ListPage, error) {
+	req, err := c.suggestionsRequest(ctx, q)
 	if err != nil {
 		return nil, err
 	}
@@ -351,35 +419,53 @@ func (c *Client) GetSeason(q *SeasonalQuery) (*ListPage, error) {
 	return &res, nil
 }
 
-// GetListQS performs a query based on a provided query string. Allows queries to be constructed elsewhere,
-// such as the frontend or from a previous/next link. Specifically intended for resouces that return ListPage
-// result objects (GetAnime, GetSeason)
-func (c *Client) GetListQS(qs string) (*ListPage, error) {
-	req, err := http.NewRequest(http.MethodGet, qs, nil)
-	if err != nil {
-		return nil, err
+// suggestionsRequest builds the request for a suggestions query, filling in any defaults and checking the query
+// is valid
+func (c *Client) suggestionsRequest(ctx context.Context, q *SuggestionsQuery) (*http.Request, error) {
+	// Check for required values
+	if c.TokenSource == nil {
+		return nil, ErrAuthRequired
+	}
+	// Handle defaults
+	if q.Limit == 0 {
+		q.Limit = 100
+	} else if q.Limit > SmallQueryLimit {
+		q.Limit = SmallQueryLimit
+	}
+	if len(q.Fields) == 0 {
+		q.Fields = BasicFieldQuery
 	}
 
-	res := ListPage{}
-	if err := c.sendRequest(req, &res); err != nil {
+	if err := q.Fields.validate(EndpointSuggestions, mediaAnime); err != nil {
 		return nil, err
 	}
 
-	return &res, nil
+	queryFields := q.Fields.ToString()
+	queryString := fmt.Sprintf("%s/anime/suggestions?limit=%d&offset=%d&fields=%s", c.BaseURL, q.Limit, q.Offset, queryFields)
+	return http.NewRequestWithContext(ctx, http.MethodGet, queryString, nil)
+}
+
+// GetListQS performs a query based on a provided query string. Allows queries to be constructed elsewhere,
+// such as the frontend or from a previous/next link. Specifically intended for resouces that return ListPage
+// result objects (GetAnime, GetSeason, GetSuggestions)
+func (c *Client) GetListQS(qs string) (*ListPage, error) {
+	return c.GetListQSContext(context.Background(), qs)
+}
+
+// GetListQSContext is the same as GetListQS, but the request is bound to the provided context. Cancelling the
+// context aborts the request.
+func (c *Client) GetListQSContext(ctx context.Context, qs string) (*ListPage, error) {
+	return GetPageQS[Listing](ctx, c, qs)
 }
 
 // GetRankingQS performs a query based on a provided query string. Allows queries to be constructed elsewhere,
 // such as the frontend or from a previous/next link. Specifically intended for Ranking resouce.
 func (c *Client) GetRankingQS(qs string) (*RankingPage, error) {
-	req, err := http.NewRequest(http.MethodGet, qs, nil)
-	if err != nil {
-		return nil, err
-	}
-
-	res := RankingPage{}
-	if err := c.sendRequest(req, &res); err != nil {
-		return nil, err
-	}
+	return c.GetRankingQSContext(context.Background(), qs)
+}
 
-	return &res, nil
+// GetRankingQSContext is the same as GetRankingQS, but the request is bound to the provided context. Cancelling the
+// context aborts the request.
+func (c *Client) GetRankingQSContext(ctx context.Context, qs string) (*RankingPage, error) {
+	return GetPageQS[Ranking](ctx, c, qs)
 }
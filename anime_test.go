@@ -0,0 +1,48 @@
+package malgomate
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/fuzzylimes/malgomate/auth"
+)
+
+func TestGetSuggestions(t *testing.T) {
+	testCases := []struct {
+		ts  auth.TokenSource
+		err error
+	}{
+		{nil, ErrAuthRequired},
+		{auth.StaticTokenSource(&auth.Token{AccessToken: "abc"}), nil},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("Test case %d", i), func(t *testing.T) {
+			var calls int
+			c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				calls++
+				if r.URL.Path != "/anime/suggestions" || r.URL.RawQuery != "limit=100&offset=5&fields=id,title,main_picture" {
+					t.Errorf("Unexpected request %q", r.URL)
+				}
+				fmt.Fprint(w, `{"data": [{"node": {"id": 1, "title": "Cowboy Bebop"}}], "paging": {}}`)
+			})
+			c.TokenSource = tc.ts
+
+			res, err := c.GetSuggestions(&SuggestionsQuery{Limit: 1000, Offset: 5})
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("Expected %v, got %v", tc.err, err)
+			}
+			if tc.err != nil {
+				if calls != 0 {
+					t.Errorf("Expected no request to be made without bearer auth")
+				}
+				return
+			}
+			if len(res.Data) != 1 || res.Data[0].Node.Title != "Cowboy Bebop" {
+				t.Errorf("Unexpected listing %+v", res.Data)
+			}
+		})
+	}
+}
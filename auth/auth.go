@@ -0,0 +1,256 @@
+// Package auth implements the OAuth2 PKCE authorization flow used by the MAL (MyAnimeList) API. It can be used
+// on its own to obtain user tokens, or plugged into a malgomate Client so requests are sent with a bearer token.
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	AuthURL  = "https://myanimelist.net/v1/oauth2/authorize"
+	TokenURL = "https://myanimelist.net/v1/oauth2/token"
+)
+
+// expiryDelta is how long before its actual expiry a token is considered expired, so that it is refreshed
+// before MAL starts rejecting it
+const expiryDelta = time.Minute
+
+// Config holds the details of an application registered in the MAL dev console. Recommended to initialize via
+// the NewConfig constructor, but the AuthURL and TokenURL can be overridden by hand (e.g. to point at a local
+// token server when testing).
+type Config struct {
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	AuthURL      string
+	TokenURL     string
+	HTTPClient   *http.Client
+}
+
+// NewConfig is a constructor for quickly building a Config pointed at MAL. The client secret is only required
+// for applications registered as a "web" app type, and can be left empty otherwise.
+func NewConfig(clientID, clientSecret, redirectURL string) *Config {
+	return &Config{
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		RedirectURL:  redirectURL,
+		AuthURL:      AuthURL,
+		TokenURL:     TokenURL,
+		HTTPClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+	}
+}
+
+// Token is an OAuth2 token returned by MAL
+type Token struct {
+	TokenType    string    `json:"token_type"`
+	AccessToken  string    `json:"access_token"`
+	RefreshToken string    `json:"refresh_token"`
+	ExpiresIn    int       `json:"expires_in"`
+	Expiry       time.Time `json:"expiry,omitempty"`
+}
+
+// Valid checks to see if the token is set and not about to expire
+func (t *Token) Valid() bool {
+	if t == nil || t.AccessToken == "" {
+		return false
+	}
+	return t.Expiry.IsZero() || time.Now().Add(expiryDelta).Before(t.Expiry)
+}
+
+// TokenError is returned when the token endpoint rejects a request
+type TokenError struct {
+	StatusCode  int
+	Code        string `json:"error"`
+	Message     string `json:"message"`
+	Description string `json:"error_description"`
+	Hint        string `json:"hint"`
+}
+
+// Error implements the error interface
+func (e *TokenError) Error() string {
+	msg := e.Message
+	if msg == "" {
+		msg = e.Description
+	}
+	if msg == "" {
+		msg = e.Code
+	}
+	return fmt.Sprintf("token request failed: %s (status code: %d)", msg, e.StatusCode)
+}
+
+// NewCodeVerifier generates a random PKCE code verifier. MAL only supports the "plain" code challenge method,
+// so the verifier is also used as the code challenge.
+func NewCodeVerifier() (string, error) {
+	b := make([]byte, 64)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	// 64 random bytes encode to 86 characters, well within the 43-128 characters allowed by RFC 7636
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// AuthCodeURL builds the URL that the user should be sent to in order to authorize the application. The
+// state value is echoed back to the redirect URL and should be checked by the caller.
+func (c *Config) AuthCodeURL(state, codeVerifier string) string {
+	v := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {c.ClientID},
+		"code_challenge":        {codeVerifier},
+		"code_challenge_method": {"plain"},
+	}
+	if state != "" {
+		v.Set("state", state)
+	}
+	if c.RedirectURL != "" {
+		v.Set("redirect_uri", c.RedirectURL)
+	}
+	return c.AuthURL + "?" + v.Encode()
+}
+
+// Exchange trades the authorization code sent to the redirect URL for a token. The code verifier must be the
+// same one that was used to build the AuthCodeURL.
+func (c *Config) Exchange(ctx context.Context, code, codeVerifier string) (*Token, error) {
+	if code == "" {
+		return nil, errors.New("missing required parameter: code must be set")
+	}
+	v := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"code_verifier": {codeVerifier},
+	}
+	if c.RedirectURL != "" {
+		v.Set("redirect_uri", c.RedirectURL)
+	}
+	return c.retrieveToken(ctx, v)
+}
+
+// Refresh uses a refresh token to obtain a new token
+func (c *Config) Refresh(ctx context.Context, refreshToken string) (*Token, error) {
+	if refreshToken == "" {
+		return nil, errors.New("missing required parameter: refresh token must be set")
+	}
+	return c.retrieveToken(ctx, url.Values{
+		"grant_type":    {"refresh_token"},
+		"refresh_token": {refreshToken},
+	})
+}
+
+// retrieveToken handles all requests made against the token endpoint
+func (c *Config) retrieveToken(ctx context.Context, v url.Values) (*Token, error) {
+	v.Set("client_id", c.ClientID)
+	if c.ClientSecret != "" {
+		v.Set("client_secret", c.ClientSecret)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.TokenURL, strings.NewReader(v.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	httpClient := c.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	res, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode >= 400 {
+		tokenErr := &TokenError{StatusCode: res.StatusCode}
+		json.NewDecoder(res.Body).Decode(tokenErr)
+		return nil, tokenErr
+	}
+
+	tok := &Token{}
+	if err := json.NewDecoder(res.Body).Decode(tok); err != nil {
+		return nil, err
+	}
+	if tok.AccessToken == "" {
+		return nil, errors.New("token response did not contain an access token")
+	}
+	if tok.ExpiresIn > 0 {
+		tok.Expiry = time.Now().Add(time.Duration(tok.ExpiresIn) * time.Second)
+	}
+	return tok, nil
+}
+
+// TokenSource hands out valid tokens
+type TokenSource interface {
+	Token(ctx context.Context) (*Token, error)
+}
+
+// refreshingSource is a TokenSource that refreshes its token shortly before it expires
+type refreshingSource struct {
+	mu     sync.Mutex
+	config *Config
+	token  *Token
+	notify func(*Token)
+}
+
+// TokenSource returns a TokenSource that starts with the provided token and automatically refreshes it before
+// it expires. The optional onRefresh function is called with every newly refreshed token, which is useful for
+// persisting tokens between runs. The returned TokenSource is safe for concurrent use.
+func (c *Config) TokenSource(tok *Token, onRefresh func(*Token)) TokenSource {
+	return &refreshingSource{
+		config: c,
+		token:  tok,
+		notify: onRefresh,
+	}
+}
+
+// Token returns the current token, refreshing it first if it is about to expire
+func (rs *refreshingSource) Token(ctx context.Context) (*Token, error) {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	if rs.token.Valid() {
+		return rs.token, nil
+	}
+	if rs.token == nil || rs.token.RefreshToken == "" {
+		return nil, errors.New("token expired and no refresh token is available")
+	}
+
+	tok, err := rs.config.Refresh(ctx, rs.token.RefreshToken)
+	if err != nil {
+		return nil, err
+	}
+	if tok.RefreshToken == "" {
+		tok.RefreshToken = rs.token.RefreshToken
+	}
+	rs.token = tok
+	if rs.notify != nil {
+		rs.notify(tok)
+	}
+	return tok, nil
+}
+
+// staticSource is a TokenSource that always returns the same token
+type staticSource struct {
+	token *Token
+}
+
+// StaticTokenSource returns a TokenSource that always returns the provided token, without ever refreshing it
+func StaticTokenSource(tok *Token) TokenSource {
+	return &staticSource{token: tok}
+}
+
+// Token returns the static token
+func (ss *staticSource) Token(ctx context.Context) (*Token, error) {
+	return ss.token, nil
+}
@@ -0,0 +1,159 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newTokenServer spins up a stand-in for the MAL token endpoint. Every token it hands out is numbered, so
+// tests can tell which token they received.
+func newTokenServer(t *testing.T, expiresIn int) (*Config, *int32) {
+	t.Helper()
+	var issued int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil || r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if r.PostForm.Get("client_id") != "client" {
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(w, `{"error":"invalid_client","message":"Client authentication failed"}`)
+			return
+		}
+
+		switch r.PostForm.Get("grant_type") {
+		case "authorization_code":
+			if r.PostForm.Get("code") != "good-code" || r.PostForm.Get("code_verifier") != "verifier" {
+				w.WriteHeader(http.StatusBadRequest)
+				fmt.Fprint(w, `{"error":"invalid_grant","message":"Authorization code is invalid"}`)
+				return
+			}
+		case "refresh_token":
+			if r.PostForm.Get("refresh_token") == "" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		n := atomic.AddInt32(&issued, 1)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"token_type":    "Bearer",
+			"expires_in":    expiresIn,
+			"access_token":  fmt.Sprintf("access-%d", n),
+			"refresh_token": fmt.Sprintf("refresh-%d", n),
+		})
+	}))
+	t.Cleanup(srv.Close)
+
+	c := NewConfig("client", "", "http://localhost/callback")
+	c.TokenURL = srv.URL
+	return c, &issued
+}
+
+func TestAuthCodeURL(t *testing.T) {
+	c := NewConfig("client", "", "http://localhost/callback")
+	u, err := url.Parse(c.AuthCodeURL("state", "verifier"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %q", err)
+	}
+
+	expected := map[string]string{
+		"response_type":         "code",
+		"client_id":             "client",
+		"state":                 "state",
+		"redirect_uri":          "http://localhost/callback",
+		"code_challenge":        "verifier",
+		"code_challenge_method": "plain",
+	}
+	for k, v := range expected {
+		if got := u.Query().Get(k); got != v {
+			t.Errorf("Expected %s to be %q, got %q", k, v, got)
+		}
+	}
+}
+
+func TestNewCodeVerifier(t *testing.T) {
+	a, err := NewCodeVerifier()
+	if err != nil {
+		t.Fatalf("Unexpected error: %q", err)
+	}
+	b, _ := NewCodeVerifier()
+	if len(a) < 43 || len(a) > 128 {
+		t.Errorf("Expected verifier length between 43 and 128, got %d", len(a))
+	}
+	if a == b {
+		t.Errorf("Expected verifiers to be random")
+	}
+}
+
+func TestExchange(t *testing.T) {
+	testCases := []struct {
+		code     string
+		verifier string
+		errCode  string
+	}{
+		{"good-code", "verifier", ""},
+		{"bad-code", "verifier", "invalid_grant"},
+		{"good-code", "wrong", "invalid_grant"},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("Test case %d", i), func(t *testing.T) {
+			c, _ := newTokenServer(t, 3600)
+			tok, err := c.Exchange(context.Background(), tc.code, tc.verifier)
+			if tc.errCode != "" {
+				var tokenErr *TokenError
+				if !errors.As(err, &tokenErr) || tokenErr.Code != tc.errCode {
+					t.Fatalf("Expected token error %q, got %v", tc.errCode, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %q", err)
+			}
+			if tok.AccessToken != "access-1" || tok.RefreshToken != "refresh-1" || !tok.Valid() {
+				t.Errorf("Unexpected token %+v", tok)
+			}
+		})
+	}
+}
+
+func TestTokenSourceRefresh(t *testing.T) {
+	c, issued := newTokenServer(t, 3600)
+
+	var refreshed []*Token
+	expired := &Token{AccessToken: "old", RefreshToken: "refresh-0", Expiry: time.Now().Add(30 * time.Second)}
+	ts := c.TokenSource(expired, func(tok *Token) { refreshed = append(refreshed, tok) })
+
+	for n := 0; n < 3; n++ {
+		tok, err := ts.Token(context.Background())
+		if err != nil {
+			t.Fatalf("Unexpected error: %q", err)
+		}
+		if tok.AccessToken != "access-1" {
+			t.Errorf("Expected the refreshed token to be reused, got %q", tok.AccessToken)
+		}
+	}
+	if *issued != 1 || len(refreshed) != 1 {
+		t.Errorf("Expected exactly one refresh, got %d issued and %d notified", *issued, len(refreshed))
+	}
+}
+
+func TestTokenSourceNoRefreshToken(t *testing.T) {
+	c, _ := newTokenServer(t, 3600)
+	ts := c.TokenSource(&Token{AccessToken: "old", Expiry: time.Now().Add(-time.Hour)}, nil)
+	if _, err := ts.Token(context.Background()); err == nil {
+		t.Errorf("Expected an error when the token can't be refreshed")
+	}
+}
@@ -0,0 +1,69 @@
+package malgomate
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/fuzzylimes/malgomate/auth"
+)
+
+func TestClientAuthentication(t *testing.T) {
+	testCases := []struct {
+		ts         auth.TokenSource
+		authHeader string
+		clientID   string
+	}{
+		{nil, "", "test-key"},
+		{auth.StaticTokenSource(&auth.Token{AccessToken: "abc"}), "Bearer abc", ""},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("Test case %d", i), func(t *testing.T) {
+			c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				if got := r.Header.Get("Authorization"); got != tc.authHeader {
+					t.Errorf("Expected Authorization %q, got %q", tc.authHeader, got)
+				}
+				if got := r.Header.Get("X-MAL-CLIENT-ID"); got != tc.clientID {
+					t.Errorf("Expected X-MAL-CLIENT-ID %q, got %q", tc.clientID, got)
+				}
+				fmt.Fprint(w, `{"id":1,"title":"Test"}`)
+			})
+			c.TokenSource = tc.ts
+
+			if _, err := c.GetDetails(&DetailsQuery{Id: 1}); err != nil {
+				t.Errorf("Unexpected error: %q", err)
+			}
+		})
+	}
+}
+
+func TestClientAuthenticationRefresh(t *testing.T) {
+	var tokenCalls int
+	tokenSrv := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		tokenCalls++
+		fmt.Fprint(w, `{"token_type":"Bearer","expires_in":3600,"access_token":"fresh","refresh_token":"r2"}`)
+	})
+	conf := auth.NewConfig("client", "", "")
+	conf.TokenURL = tokenSrv.BaseURL
+
+	api := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "Bearer fresh" {
+			t.Errorf("Expected the refreshed token to be used, got %q", got)
+		}
+		fmt.Fprint(w, `{"id":1,"title":"Test"}`)
+	})
+	c := NewAuthClient(conf.TokenSource(&auth.Token{AccessToken: "stale", RefreshToken: "r1", Expiry: time.Now()}, nil))
+	c.BaseURL = api.BaseURL
+
+	for n := 0; n < 2; n++ {
+		if _, err := c.GetDetailsContext(context.Background(), &DetailsQuery{Id: 1}); err != nil {
+			t.Fatalf("Unexpected error: %q", err)
+		}
+	}
+	if tokenCalls != 1 {
+		t.Errorf("Expected a single refresh, got %d", tokenCalls)
+	}
+}
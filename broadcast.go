@@ -0,0 +1,130 @@
+package malgomate
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// JST is Japan Standard Time, the time zone that MAL broadcast times are given in
+var JST = time.FixedZone("JST", 9*60*60)
+
+// week is the time between two broadcasts
+const week = 7 * 24 * time.Hour
+
+// weekdays maps the day_of_the_week values sent by MAL to their time.Weekday
+var weekdays = map[string]time.Weekday{
+	"sunday":    time.Sunday,
+	"monday":    time.Monday,
+	"tuesday":   time.Tuesday,
+	"wednesday": time.Wednesday,
+	"thursday":  time.Thursday,
+	"friday":    time.Friday,
+	"saturday":  time.Saturday,
+}
+
+// Weekday returns the day of the week the anime airs on in Japan. The second value is false for anime that
+// don't air on a set day, which MAL sends as "other", and when the broadcast is nil.
+func (b *Broadcast) Weekday() (time.Weekday, bool) {
+	if b == nil {
+		return 0, false
+	}
+	wd, ok := weekdays[strings.ToLower(b.DayOfTheWeek)]
+	return wd, ok
+}
+
+// clock parses the start time into hours and minutes. Late night times past midnight, such as "25:30", are
+// accepted and roll over into the next day.
+func (b *Broadcast) clock() (int, int, error) {
+	var h, m int
+	if _, err := fmt.Sscanf(b.StartTime, "%d:%d", &h, &m); err != nil || h < 0 || m < 0 || m > 59 {
+		return 0, 0, fmt.Errorf("%w: invalid start time %q", ErrNoBroadcast, b.StartTime)
+	}
+	return h, m, nil
+}
+
+// NextAiring works out the first broadcast at or after t, returned in loc. Locations default to JST when nil.
+// Returns ErrNoBroadcast when the day or start time of the broadcast isn't known.
+func (b *Broadcast) NextAiring(t time.Time, loc *time.Location) (time.Time, error) {
+	if b == nil {
+		return time.Time{}, ErrNoBroadcast
+	}
+	wd, ok := b.Weekday()
+	if !ok {
+		return time.Time{}, fmt.Errorf("%w: airs on %q", ErrNoBroadcast, b.DayOfTheWeek)
+	}
+	h, m, err := b.clock()
+	if err != nil {
+		return time.Time{}, err
+	}
+	if loc == nil {
+		loc = JST
+	}
+
+	// Start a week back to cover start times that roll over into the next day
+	jt := t.In(JST)
+	day := jt.Day() - 7 + (int(wd)-int(jt.Weekday())+7)%7
+	next := time.Date(jt.Year(), jt.Month(), day, h, m, 0, 0, JST)
+	for next.Before(t) {
+		next = next.Add(week)
+	}
+	return next.In(loc), nil
+}
+
+// AiringsBetween lists every broadcast from the start instant up to, but not including, the end instant, returned
+// in loc. Locations default to JST when nil.
+func (b *Broadcast) AiringsBetween(from, to time.Time, loc *time.Location) ([]time.Time, error) {
+	next, err := b.NextAiring(from, loc)
+	if err != nil {
+		return nil, err
+	}
+
+	var airings []time.Time
+	for ; next.Before(to); next = next.Add(week) {
+		airings = append(airings, next)
+	}
+	return airings, nil
+}
+
+// FirstAiring works out when the first episode was broadcast, from the anime's start date and broadcast time.
+// Requires the start_date and broadcast fields, and a start date with a known day.
+func (a *Anime) FirstAiring(loc *time.Location) (time.Time, error) {
+	if a.StartDate == nil || a.StartDate.Precision != PrecisionDay {
+		return time.Time{}, fmt.Errorf("%w: start date is not known", ErrNoBroadcast)
+	}
+	return a.Broadcast.NextAiring(a.StartDate.Time(JST), loc)
+}
+
+// CurrentEpisode estimates the latest episode that has been broadcast at time t, assuming one episode airs every
+// week from the start date without any breaks. Returns 0 before the first episode has aired, and never more than
+// the number of episodes when that is known. Requires the start_date and broadcast fields, along with
+// num_episodes to cap the result.
+func (a *Anime) CurrentEpisode(t time.Time) (int, error) {
+	first, err := a.FirstAiring(JST)
+	if err != nil {
+		return 0, err
+	}
+	if t.Before(first) {
+		return 0, nil
+	}
+
+	ep := int(t.Sub(first)/week) + 1
+	if a.NumEpisodes > 0 && ep > a.NumEpisodes {
+		ep = a.NumEpisodes
+	}
+	return ep, nil
+}
+
+// ExpectedFinish estimates when the last episode will be broadcast, returned in loc, assuming one episode airs
+// every week from the start date without any breaks. Locations default to JST when nil. Requires the start_date,
+// broadcast and num_episodes fields, and returns ErrNoBroadcast when the number of episodes isn't known yet.
+func (a *Anime) ExpectedFinish(loc *time.Location) (time.Time, error) {
+	if a.NumEpisodes <= 0 {
+		return time.Time{}, fmt.Errorf("%w: number of episodes is not known", ErrNoBroadcast)
+	}
+	first, err := a.FirstAiring(loc)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return first.Add(time.Duration(a.NumEpisodes-1) * week), nil
+}
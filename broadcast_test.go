@@ -0,0 +1,139 @@
+package malgomate
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestBroadcastWeekday(t *testing.T) {
+	testCases := []struct {
+		b        *Broadcast
+		expected time.Weekday
+		ok       bool
+	}{
+		{&Broadcast{DayOfTheWeek: "saturday"}, time.Saturday, true},
+		{&Broadcast{DayOfTheWeek: "Sunday"}, time.Sunday, true},
+		{&Broadcast{DayOfTheWeek: "other"}, 0, false},
+		{nil, 0, false},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("Test case %d", i), func(t *testing.T) {
+			got, ok := tc.b.Weekday()
+			if got != tc.expected || ok != tc.ok {
+				t.Errorf("Expected %s %t, got %s %t", tc.expected, tc.ok, got, ok)
+			}
+		})
+	}
+}
+
+func TestNextAiring(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		ny = time.FixedZone("EST", -5*60*60)
+	}
+	saturday := &Broadcast{DayOfTheWeek: "saturday", StartTime: "23:30"}
+
+	testCases := []struct {
+		b        *Broadcast
+		t        time.Time
+		loc      *time.Location
+		expected time.Time
+		err      error
+	}{
+		// Friday in Japan
+		{saturday, time.Date(2022, 1, 7, 12, 0, 0, 0, JST), nil, time.Date(2022, 1, 8, 23, 30, 0, 0, JST), nil},
+		// Exactly on air
+		{saturday, time.Date(2022, 1, 8, 23, 30, 0, 0, JST), nil, time.Date(2022, 1, 8, 23, 30, 0, 0, JST), nil},
+		// Just missed it
+		{saturday, time.Date(2022, 1, 8, 23, 31, 0, 0, JST), nil, time.Date(2022, 1, 15, 23, 30, 0, 0, JST), nil},
+		// Saturday morning in New York is already Saturday night in Japan
+		{saturday, time.Date(2022, 1, 8, 9, 0, 0, 0, ny), ny, time.Date(2022, 1, 8, 9, 30, 0, 0, ny), nil},
+		// Late night slot rolls over into Monday
+		{&Broadcast{DayOfTheWeek: "sunday", StartTime: "25:05"}, time.Date(2022, 1, 10, 0, 0, 0, 0, JST), nil, time.Date(2022, 1, 10, 1, 5, 0, 0, JST), nil},
+		{&Broadcast{DayOfTheWeek: "other"}, time.Now(), nil, time.Time{}, ErrNoBroadcast},
+		{&Broadcast{DayOfTheWeek: "monday"}, time.Now(), nil, time.Time{}, ErrNoBroadcast},
+		{nil, time.Now(), nil, time.Time{}, ErrNoBroadcast},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("Test case %d", i), func(t *testing.T) {
+			got, err := tc.b.NextAiring(tc.t, tc.loc)
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("Expected error %v, got %v", tc.err, err)
+			}
+			if !got.Equal(tc.expected) {
+				t.Errorf("Expected %s, got %s", tc.expected, got)
+			}
+			if tc.loc != nil && got.Location() != tc.loc {
+				t.Errorf("Expected time in %s, got %s", tc.loc, got.Location())
+			}
+		})
+	}
+}
+
+func TestAiringsBetween(t *testing.T) {
+	b := &Broadcast{DayOfTheWeek: "wednesday", StartTime: "01:00"}
+	got, err := b.AiringsBetween(time.Date(2022, 1, 1, 0, 0, 0, 0, JST), time.Date(2022, 1, 26, 1, 0, 0, 0, JST), time.UTC)
+	if err != nil {
+		t.Fatalf("Unexpected error: %q", err)
+	}
+
+	expected := []time.Time{
+		time.Date(2022, 1, 4, 16, 0, 0, 0, time.UTC),
+		time.Date(2022, 1, 11, 16, 0, 0, 0, time.UTC),
+		time.Date(2022, 1, 18, 16, 0, 0, 0, time.UTC),
+	}
+	if fmt.Sprint(got) != fmt.Sprint(expected) {
+		t.Errorf("Expected %v, got %v", expected, got)
+	}
+}
+
+func TestEpisodeEstimates(t *testing.T) {
+	start, _ := ParsePartialDate("2022-01-09")
+	month, _ := ParsePartialDate("2022-01")
+	a := Anime{StartDate: &start, NumEpisodes: 12, Broadcast: &Broadcast{DayOfTheWeek: "sunday", StartTime: "24:00"}}
+
+	testCases := []struct {
+		a        Anime
+		t        time.Time
+		expected int
+		err      error
+	}{
+		{a, time.Date(2022, 1, 9, 12, 0, 0, 0, JST), 0, nil},
+		{a, time.Date(2022, 1, 10, 0, 0, 0, 0, JST), 1, nil},
+		{a, time.Date(2022, 1, 23, 12, 0, 0, 0, JST), 2, nil},
+		{a, time.Date(2022, 1, 24, 0, 0, 0, 0, JST), 3, nil},
+		{a, time.Date(2023, 1, 1, 0, 0, 0, 0, JST), 12, nil},
+		{Anime{StartDate: &start, Broadcast: a.Broadcast}, time.Date(2023, 1, 1, 0, 0, 0, 0, JST), 51, nil},
+		{Anime{StartDate: &month, Broadcast: a.Broadcast}, time.Now(), 0, ErrNoBroadcast},
+		{Anime{StartDate: &start}, time.Now(), 0, ErrNoBroadcast},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("Test case %d", i), func(t *testing.T) {
+			got, err := tc.a.CurrentEpisode(tc.t)
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("Expected error %v, got %v", tc.err, err)
+			}
+			if got != tc.expected {
+				t.Errorf("Expected %d, got %d", tc.expected, got)
+			}
+		})
+	}
+
+	finish, err := a.ExpectedFinish(time.UTC)
+	if err != nil {
+		t.Fatalf("Unexpected error: %q", err)
+	}
+	if expected := time.Date(2022, 3, 27, 15, 0, 0, 0, time.UTC); !finish.Equal(expected) {
+		t.Errorf("Expected %s, got %s", expected, finish)
+	}
+
+	a.NumEpisodes = 0
+	if _, err := a.ExpectedFinish(nil); !errors.Is(err, ErrNoBroadcast) {
+		t.Errorf("Expected ErrNoBroadcast, got %v", err)
+	}
+}
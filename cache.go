@@ -0,0 +1,418 @@
+package malgomate
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// EndpointKind groups MAL endpoints by the kind of data they return, so that each kind can be cached for a
+// different amount of time
+type EndpointKind string
+
+// EndpointKind values cover every endpoint supported by the Client. Anime and manga endpoints of the same
+// shape share a kind.
+const (
+	EndpointDetails     EndpointKind = "details"
+	EndpointList        EndpointKind = "list"
+	EndpointRanking     EndpointKind = "ranking"
+	EndpointSeason      EndpointKind = "season"
+	EndpointSuggestions EndpointKind = "suggestions"
+	EndpointUserList    EndpointKind = "user_list"
+	EndpointUser        EndpointKind = "user"
+	EndpointForum       EndpointKind = "forum"
+	EndpointOther       EndpointKind = "other"
+)
+
+// endpointKind works out which kind of endpoint a request URL points at
+func endpointKind(u *url.URL) EndpointKind {
+	segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+	for i, segment := range segments {
+		rest := segments[i+1:]
+		switch segment {
+		case "anime", "manga":
+			switch {
+			case len(rest) == 0:
+				return EndpointList
+			case rest[0] == "ranking":
+				return EndpointRanking
+			case rest[0] == "season":
+				return EndpointSeason
+			case rest[0] == "suggestions":
+				return EndpointSuggestions
+			case len(rest) == 1:
+				return EndpointDetails
+			}
+			return EndpointOther
+		case "users":
+			if len(rest) > 1 && rest[1] == "animelist" {
+				return EndpointUserList
+			}
+			return EndpointUser
+		case "forum":
+			return EndpointForum
+		}
+	}
+	return EndpointOther
+}
+
+// CacheEntry is a cached response body, along with when it was stored and when it expires
+type CacheEntry struct {
+	Body     []byte    `json:"body"`
+	StoredAt time.Time `json:"stored_at"`
+	Expires  time.Time `json:"expires"`
+}
+
+// Fresh checks to see if the entry has not expired yet
+func (e *CacheEntry) Fresh() bool {
+	return time.Now().Before(e.Expires)
+}
+
+// Cache stores response bodies keyed by their normalized request URL (see CacheKey). Entries should be handed
+// back even once they have expired, as the Client decides what to do with expired entries. Implementations must
+// be safe for concurrent use.
+type Cache interface {
+	Get(key string) (*CacheEntry, bool)
+	Set(key string, entry *CacheEntry)
+	Delete(key string)
+}
+
+// CacheStats are the number of cache hits and misses seen by a Client. Stale counts the hits that were served
+// from an expired entry, either while it was being revalidated or as an offline fallback.
+type CacheStats struct {
+	Hits   uint64
+	Misses uint64
+	Stale  uint64
+}
+
+// cacheStats keeps count of a client's cache hits and misses
+type cacheStats struct {
+	mu    sync.Mutex
+	stats CacheStats
+}
+
+// record counts a single cache lookup
+func (cs *cacheStats) record(hit, stale bool) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	if hit {
+		cs.stats.Hits++
+	} else {
+		cs.stats.Misses++
+	}
+	if stale {
+		cs.stats.Stale++
+	}
+}
+
+// CacheStats reports the number of cache hits and misses the client has seen so far
+func (c *Client) CacheStats() CacheStats {
+	c.cacheStats.mu.Lock()
+	defer c.cacheStats.mu.Unlock()
+	return c.cacheStats.stats
+}
+
+// ResponseMeta describes where the response to a request came from. Attach one to a context with
+// WithResponseMeta and it is filled in by the request made with that context.
+type ResponseMeta struct {
+	// FromCache is set when the response was served from the cache
+	FromCache bool
+	// Stale is set when the cached response had already expired
+	Stale bool
+	// StoredAt is when the cached response was originally fetched
+	StoredAt time.Time
+	// Revalidating is set when a background refresh of the stale response was started
+	Revalidating bool
+	// FallbackErr is the error that caused a stale response to be served in offline mode
+	FallbackErr error
+}
+
+// responseMetaKey is the context key used by WithResponseMeta
+type responseMetaKey struct{}
+
+// WithResponseMeta returns a copy of the context that reports details of the response through meta. Useful for
+// telling cached and stale responses apart from fresh ones:
+//
+//	var meta mal.ResponseMeta
+//	res, err := c.GetDetailsContext(mal.WithResponseMeta(ctx, &meta), q)
+//	if meta.Stale { ... }
+func WithResponseMeta(ctx context.Context, meta *ResponseMeta) context.Context {
+	return context.WithValue(ctx, responseMetaKey{}, meta)
+}
+
+// setResponseMeta fills in the ResponseMeta attached to the context, if there is one
+func setResponseMeta(ctx context.Context, meta ResponseMeta) {
+	if m, ok := ctx.Value(responseMetaKey{}).(*ResponseMeta); ok {
+		*m = meta
+	}
+}
+
+// cacheTTL looks up how long the response to a request should be cached for. Only GET requests are cached, and
+// never when the response belongs to the authenticated user.
+func (c *Client) cacheTTL(req *http.Request) time.Duration {
+	if c.Cache == nil || req.Method != http.MethodGet || c.userScoped(req) {
+		return 0
+	}
+	return c.CacheTTL[endpointKind(req.URL)]
+}
+
+// userScoped checks to see if a request is made on behalf of a user and asks for something only that user can
+// see: their profile, their list, their suggestions or the my_list_status of an entry. Cache keys are built from
+// the URL alone, so caching these responses would hand one user's data to anyone sharing the cache.
+func (c *Client) userScoped(req *http.Request) bool {
+	if c.TokenSource == nil && req.Header.Get("Authorization") == "" {
+		return false
+	}
+	switch endpointKind(req.URL) {
+	case EndpointUser, EndpointUserList, EndpointSuggestions:
+		return true
+	}
+	return strings.Contains(req.URL.Query().Get("fields"), string(FieldMyListStatus))
+}
+
+// KeyLister is implemented by caches that can list the keys they hold. The Client uses it to evict the entries
+// made out of date by a change to the user's list. Both LRUCache and DiskCache implement it; entries held by
+// caches that don't are left to expire.
+type KeyLister interface {
+	Keys() []string
+}
+
+// invalidateListWrite evicts the cached entries affected by a change to the list entry for an anime: its details,
+// and every cached user list
+func (c *Client) invalidateListWrite(id int) {
+	lister, ok := c.Cache.(KeyLister)
+	if !ok {
+		return
+	}
+	details := fmt.Sprintf("/anime/%d", id)
+	for _, key := range lister.Keys() {
+		u, err := url.Parse(key)
+		if err != nil {
+			continue
+		}
+		switch endpointKind(u) {
+		case EndpointUserList:
+			c.Cache.Delete(key)
+		case EndpointDetails:
+			if strings.HasSuffix(strings.TrimSuffix(u.Path, "/"), details) {
+				c.Cache.Delete(key)
+			}
+		}
+	}
+}
+
+// fetchCached serves a request's response body from the cache when there is a fresh entry for it, otherwise
+// making the API call and caching the response for the supplied TTL. Expired entries are served right away when
+// the client has StaleWhileRevalidate set, and are fallen back on when MAL can't be reached if OfflineFallback is
+// set.
+func (c *Client) fetchCached(req *http.Request, ttl time.Duration) ([]byte, error) {
+	key := CacheKey(req.URL)
+	entry, ok := c.Cache.Get(key)
+	if ok && entry.Fresh() {
+		c.cacheStats.record(true, false)
+		setResponseMeta(req.Context(), ResponseMeta{FromCache: true, StoredAt: entry.StoredAt})
+		return entry.Body, nil
+	}
+	if ok && c.StaleWhileRevalidate {
+		c.cacheStats.record(true, true)
+		c.revalidate(req, key, ttl)
+		setResponseMeta(req.Context(), ResponseMeta{FromCache: true, Stale: true, StoredAt: entry.StoredAt, Revalidating: true})
+		return entry.Body, nil
+	}
+
+	body, err := c.fetch(req)
+	if err != nil {
+		if ok && c.OfflineFallback && unavailable(req.Context(), err) {
+			c.cacheStats.record(true, true)
+			setResponseMeta(req.Context(), ResponseMeta{FromCache: true, Stale: true, StoredAt: entry.StoredAt, FallbackErr: err})
+			return entry.Body, nil
+		}
+		c.cacheStats.record(false, false)
+		return nil, err
+	}
+	c.cacheStats.record(false, false)
+
+	now := time.Now()
+	c.Cache.Set(key, &CacheEntry{Body: body, StoredAt: now, Expires: now.Add(ttl)})
+	setResponseMeta(req.Context(), ResponseMeta{StoredAt: now})
+
+	return body, nil
+}
+
+// revalidate refreshes a cached entry in the background. Only one refresh runs per key at a time. The refresh
+// is detached from the original request's context, so it carries on after that request has been served.
+func (c *Client) revalidate(req *http.Request, key string, ttl time.Duration) {
+	if _, running := c.revalidating.LoadOrStore(key, struct{}{}); running {
+		return
+	}
+
+	bg := req.Clone(context.Background())
+	go func() {
+		defer c.revalidating.Delete(key)
+		body, err := c.fetch(bg)
+		if err != nil {
+			return
+		}
+		now := time.Now()
+		c.Cache.Set(key, &CacheEntry{Body: body, StoredAt: now, Expires: now.Add(ttl)})
+	}()
+}
+
+// unavailable checks to see if an error means that MAL couldn't be reached, as opposed to the request itself
+// being bad. Network errors (including timeouts) and server errors count, cancelled requests do not.
+func unavailable(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return errors.Is(err, ErrServerError)
+	}
+	return true
+}
+
+// CacheKey builds the cache key for a request URL. Query parameters are sorted, as are the fields in the fields
+// parameter (at every level of nesting), so that equivalent requests share a key no matter the order their
+// fields were listed in.
+func CacheKey(u *url.URL) string {
+	q := u.Query()
+	if fields, ok := q["fields"]; ok {
+		for i, f := range fields {
+			fields[i] = normalizeFields(f)
+		}
+	}
+
+	key := *u
+	key.RawQuery = q.Encode()
+	key.Fragment = ""
+	return key.String()
+}
+
+// normalizeFields sorts and de-duplicates a comma separated list of fields, along with any sub fields
+func normalizeFields(fields string) string {
+	var parts []string
+	seen := map[string]bool{}
+	for _, f := range splitFields(fields) {
+		if i := strings.Index(f, "{"); i >= 0 && strings.HasSuffix(f, "}") {
+			f = f[:i] + "{" + normalizeFields(f[i+1:len(f)-1]) + "}"
+		}
+		if f != "" && !seen[f] {
+			seen[f] = true
+			parts = append(parts, f)
+		}
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, ",")
+}
+
+// splitFields splits a list of fields on the commas that aren't nested inside of sub fields
+func splitFields(fields string) []string {
+	var parts []string
+	depth, start := 0, 0
+	for i, r := range fields {
+		switch r {
+		case '{':
+			depth++
+		case '}':
+			depth--
+		case ',':
+			if depth == 0 {
+				parts = append(parts, strings.TrimSpace(fields[start:i]))
+				start = i + 1
+			}
+		}
+	}
+	return append(parts, strings.TrimSpace(fields[start:]))
+}
+
+// LRUCache is an in-memory Cache that holds a fixed number of entries, evicting the least recently used entry
+// once it is full
+type LRUCache struct {
+	mu       sync.Mutex
+	capacity int
+	order    *list.List
+	entries  map[string]*list.Element
+}
+
+// lruItem is the value held by each element of the LRUCache order list
+type lruItem struct {
+	key   string
+	entry *CacheEntry
+}
+
+// NewLRUCache is a constructor for an LRUCache that holds up to capacity entries
+func NewLRUCache(capacity int) *LRUCache {
+	if capacity < 1 {
+		capacity = 1
+	}
+	return &LRUCache{
+		capacity: capacity,
+		order:    list.New(),
+		entries:  map[string]*list.Element{},
+	}
+}
+
+// Get retrieves an entry, marking it as recently used
+func (lc *LRUCache) Get(key string) (*CacheEntry, bool) {
+	lc.mu.Lock()
+	defer lc.mu.Unlock()
+	el, ok := lc.entries[key]
+	if !ok {
+		return nil, false
+	}
+	lc.order.MoveToFront(el)
+	return el.Value.(*lruItem).entry, true
+}
+
+// Set stores an entry, evicting the least recently used entry if the cache is full
+func (lc *LRUCache) Set(key string, entry *CacheEntry) {
+	lc.mu.Lock()
+	defer lc.mu.Unlock()
+	if el, ok := lc.entries[key]; ok {
+		el.Value.(*lruItem).entry = entry
+		lc.order.MoveToFront(el)
+		return
+	}
+
+	lc.entries[key] = lc.order.PushFront(&lruItem{key: key, entry: entry})
+	if lc.order.Len() > lc.capacity {
+		oldest := lc.order.Back()
+		lc.order.Remove(oldest)
+		delete(lc.entries, oldest.Value.(*lruItem).key)
+	}
+}
+
+// Delete removes an entry
+func (lc *LRUCache) Delete(key string) {
+	lc.mu.Lock()
+	defer lc.mu.Unlock()
+	if el, ok := lc.entries[key]; ok {
+		lc.order.Remove(el)
+		delete(lc.entries, key)
+	}
+}
+
+// Keys lists the keys of every entry in the cache
+func (lc *LRUCache) Keys() []string {
+	lc.mu.Lock()
+	defer lc.mu.Unlock()
+	keys := make([]string, 0, len(lc.entries))
+	for key := range lc.entries {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// Len returns the number of entries in the cache
+func (lc *LRUCache) Len() int {
+	lc.mu.Lock()
+	defer lc.mu.Unlock()
+	return lc.order.Len()
+}
@@ -0,0 +1,386 @@
+package malgomate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fuzzylimes/malgomate/auth"
+)
+
+func TestCacheKey(t *testing.T) {
+	testCases := []struct {
+		a, b  string
+		equal bool
+	}{
+		{"https://x/v2/anime/1?fields=id,title", "https://x/v2/anime/1?fields=title,id", true},
+		{"https://x/v2/anime/1?fields=id,title,id", "https://x/v2/anime/1?fields=title,id", true},
+		{"https://x/v2/anime?q=a&limit=1", "https://x/v2/anime?limit=1&q=a", true},
+		{"https://x/v2/anime/1?fields=related_anime{rank,title},id", "https://x/v2/anime/1?fields=id,related_anime{title,rank}", true},
+		{"https://x/v2/anime/1?fields=related_anime{rank},title", "https://x/v2/anime/1?fields=related_anime,rank,title", false},
+		{"https://x/v2/anime/1?fields=id", "https://x/v2/anime/2?fields=id", false},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("Test case %d", i), func(t *testing.T) {
+			a, _ := url.Parse(tc.a)
+			b, _ := url.Parse(tc.b)
+			if got := CacheKey(a) == CacheKey(b); got != tc.equal {
+				t.Errorf("Expected keys %q and %q to be equal: %t", CacheKey(a), CacheKey(b), tc.equal)
+			}
+		})
+	}
+}
+
+func TestEndpointKind(t *testing.T) {
+	testCases := []struct {
+		in       string
+		expected EndpointKind
+	}{
+		{"/v2/anime", EndpointList},
+		{"/v2/anime/30230", EndpointDetails},
+		{"/v2/manga/2", EndpointDetails},
+		{"/v2/anime/ranking", EndpointRanking},
+		{"/v2/manga/ranking", EndpointRanking},
+		{"/v2/anime/season/2022/winter", EndpointSeason},
+		{"/v2/anime/suggestions", EndpointSuggestions},
+		{"/v2/anime/1/my_list_status", EndpointOther},
+		{"/v2/users/@me/animelist", EndpointUserList},
+		{"/v2/users/@me", EndpointUser},
+		{"/v2/forum/topics", EndpointForum},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("Test case %d", i), func(t *testing.T) {
+			if got := endpointKind(&url.URL{Path: tc.in}); got != tc.expected {
+				t.Errorf("Expected %s, got %s", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestLRUCache(t *testing.T) {
+	lc := NewLRUCache(2)
+	lc.Set("a", &CacheEntry{Body: []byte("a")})
+	lc.Set("b", &CacheEntry{Body: []byte("b")})
+	lc.Get("a")
+	lc.Set("c", &CacheEntry{Body: []byte("c")})
+
+	if _, ok := lc.Get("b"); ok {
+		t.Errorf("Expected the least recently used entry to be evicted")
+	}
+	for _, key := range []string{"a", "c"} {
+		if e, ok := lc.Get(key); !ok || string(e.Body) != key {
+			t.Errorf("Expected entry %q to be cached", key)
+		}
+	}
+
+	lc.Delete("a")
+	if _, ok := lc.Get("a"); ok || lc.Len() != 1 {
+		t.Errorf("Expected entry to be deleted")
+	}
+}
+
+func TestDiskCache(t *testing.T) {
+	dc, err := NewDiskCache(t.TempDir())
+	if err != nil {
+		t.Fatalf("Unexpected error: %q", err)
+	}
+
+	expires := time.Now().Add(time.Hour).Round(time.Second)
+	dc.Set("key", &CacheEntry{Body: []byte(`{"id":1}`), Expires: expires})
+	e, ok := dc.Get("key")
+	if !ok || string(e.Body) != `{"id":1}` || !e.Expires.Equal(expires) {
+		t.Fatalf("Unexpected entry %+v", e)
+	}
+
+	dc.Delete("key")
+	if _, ok := dc.Get("key"); ok {
+		t.Errorf("Expected entry to be deleted")
+	}
+}
+
+func TestClientCache(t *testing.T) {
+	testCases := []struct {
+		name  string
+		cache func(t *testing.T) Cache
+	}{
+		{"lru", func(t *testing.T) Cache { return NewLRUCache(10) }},
+		{"disk", func(t *testing.T) Cache {
+			dc, err := NewDiskCache(t.TempDir())
+			if err != nil {
+				t.Fatalf("Unexpected error: %q", err)
+			}
+			return dc
+		}},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("Test case %d (%s)", i, tc.name), func(t *testing.T) {
+			calls := map[string]int{}
+			c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				calls[r.URL.Path]++
+				fmt.Fprintf(w, `{"id":1,"title":"Call %d","data":[]}`, calls[r.URL.Path])
+			})
+			c.Cache = tc.cache(t)
+			c.CacheTTL = map[EndpointKind]time.Duration{EndpointDetails: time.Hour}
+
+			for _, fields := range []DetailFields{{DetailID, DetailTitle}, {DetailTitle, DetailID}} {
+				res, err := c.GetDetails(&DetailsQuery{Id: 1, Fields: fields})
+				if err != nil {
+					t.Fatalf("Unexpected error: %q", err)
+				}
+				if res.Title != "Call 1" {
+					t.Errorf("Expected the cached response, got %q", res.Title)
+				}
+			}
+			for n := 0; n < 2; n++ {
+				if _, err := c.GetRanking(&RankingQuery{}); err != nil {
+					t.Fatalf("Unexpected error: %q", err)
+				}
+			}
+
+			if calls["/anime/1"] != 1 || calls["/anime/ranking"] != 2 {
+				t.Errorf("Expected details to be cached and rankings not to be, got calls %v", calls)
+			}
+			if stats := c.CacheStats(); stats.Hits != 1 || stats.Misses != 1 {
+				t.Errorf("Unexpected cache stats %+v", stats)
+			}
+		})
+	}
+}
+
+func TestClientCacheExpiry(t *testing.T) {
+	var calls int
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		fmt.Fprint(w, `{"id":1,"title":"Test"}`)
+	})
+	c.Cache = NewLRUCache(10)
+	c.CacheTTL = map[EndpointKind]time.Duration{EndpointDetails: time.Millisecond}
+
+	for n := 0; n < 2; n++ {
+		if _, err := c.GetDetails(&DetailsQuery{Id: 1}); err != nil {
+			t.Fatalf("Unexpected error: %q", err)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if calls != 2 {
+		t.Errorf("Expected expired entries to be refetched, got %d calls", calls)
+	}
+}
+
+// seedExpired stores an expired details entry for anime 1 in the client's cache
+func seedExpired(t *testing.T, c *Client, title string) string {
+	t.Helper()
+	u, _ := url.Parse(c.BaseURL + "/anime/1?fields=id,title,main_picture")
+	key := CacheKey(u)
+	c.Cache.Set(key, &CacheEntry{
+		Body:     []byte(fmt.Sprintf(`{"id":1,"title":%q}`, title)),
+		StoredAt: time.Now().Add(-2 * time.Hour),
+		Expires:  time.Now().Add(-time.Hour),
+	})
+	return key
+}
+
+func TestStaleWhileRevalidate(t *testing.T) {
+	refreshed := make(chan struct{}, 1)
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id":1,"title":"Fresh"}`)
+		refreshed <- struct{}{}
+	})
+	c.Cache = NewLRUCache(10)
+	c.CacheTTL = map[EndpointKind]time.Duration{EndpointDetails: time.Hour}
+	c.StaleWhileRevalidate = true
+	key := seedExpired(t, c, "Stale")
+
+	var meta ResponseMeta
+	res, err := c.GetDetailsContext(WithResponseMeta(context.Background(), &meta), &DetailsQuery{Id: 1})
+	if err != nil {
+		t.Fatalf("Unexpected error: %q", err)
+	}
+	if res.Title != "Stale" || !meta.FromCache || !meta.Stale || !meta.Revalidating {
+		t.Errorf("Expected the stale entry to be served, got %q and %+v", res.Title, meta)
+	}
+
+	select {
+	case <-refreshed:
+	case <-time.After(2 * time.Second):
+		t.Fatalf("Expected a background refresh")
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		if e, _ := c.Cache.Get(key); e.Fresh() {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected the cache entry to be refreshed")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	res, err = c.GetDetailsContext(WithResponseMeta(context.Background(), &meta), &DetailsQuery{Id: 1})
+	if err != nil || res.Title != "Fresh" || meta.Stale {
+		t.Errorf("Expected the refreshed entry, got %q, %+v and %v", res.Title, meta, err)
+	}
+	if stats := c.CacheStats(); stats.Hits != 2 || stats.Stale != 1 {
+		t.Errorf("Unexpected cache stats %+v", stats)
+	}
+}
+
+func TestOfflineFallback(t *testing.T) {
+	testCases := []struct {
+		status   int
+		fallback bool
+		served   bool
+	}{
+		{http.StatusServiceUnavailable, true, true},
+		{http.StatusServiceUnavailable, false, false},
+		{http.StatusNotFound, true, false},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("Test case %d", i), func(t *testing.T) {
+			c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tc.status)
+			})
+			c.Cache = NewLRUCache(10)
+			c.CacheTTL = map[EndpointKind]time.Duration{EndpointDetails: time.Hour}
+			c.OfflineFallback = tc.fallback
+			seedExpired(t, c, "Stale")
+
+			var meta ResponseMeta
+			res, err := c.GetDetailsContext(WithResponseMeta(context.Background(), &meta), &DetailsQuery{Id: 1})
+			if !tc.served {
+				if err == nil {
+					t.Errorf("Expected an error, got %+v", res)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %q", err)
+			}
+			if res.Title != "Stale" || !meta.Stale || !errors.Is(meta.FallbackErr, ErrServerError) {
+				t.Errorf("Expected the stale entry to be served, got %q and %+v", res.Title, meta)
+			}
+		})
+	}
+}
+
+func TestOfflineFallbackNetworkError(t *testing.T) {
+	c := NewClient("test-key")
+	c.BaseURL = "http://127.0.0.1:1"
+	c.Cache = NewLRUCache(10)
+	c.CacheTTL = map[EndpointKind]time.Duration{EndpointDetails: time.Hour}
+	c.OfflineFallback = true
+	seedExpired(t, c, "Stale")
+
+	var meta ResponseMeta
+	res, err := c.GetDetailsContext(WithResponseMeta(context.Background(), &meta), &DetailsQuery{Id: 1})
+	if err != nil || res.Title != "Stale" || meta.FallbackErr == nil {
+		t.Errorf("Expected the stale entry to be served, got %v and %+v", err, meta)
+	}
+}
+
+func TestUserScopedResponsesNotCached(t *testing.T) {
+	testCases := []struct {
+		auth   bool
+		call   func(c *Client) error
+		cached bool
+	}{
+		{false, func(c *Client) error { _, err := c.GetDetails(&DetailsQuery{Id: 1}); return err }, true},
+		{true, func(c *Client) error { _, err := c.GetDetails(&DetailsQuery{Id: 1}); return err }, true},
+		{true, func(c *Client) error {
+			_, err := c.GetDetails(&DetailsQuery{Id: 1, Fields: DetailFields{FieldMyListStatus}})
+			return err
+		}, false},
+		{true, func(c *Client) error { _, err := c.GetUserAnimeList(&UserAnimeListQuery{}); return err }, false},
+		{false, func(c *Client) error { _, err := c.GetUserAnimeList(&UserAnimeListQuery{UserName: "a"}); return err }, true},
+		{true, func(c *Client) error { _, err := c.GetSuggestions(&SuggestionsQuery{}); return err }, false},
+		{true, func(c *Client) error { _, err := c.GetMyUserInfo(nil); return err }, false},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("Test case %d", i), func(t *testing.T) {
+			var calls int
+			c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				calls++
+				fmt.Fprint(w, `{"id":1,"data":[]}`)
+			})
+			if tc.auth {
+				c.TokenSource = auth.StaticTokenSource(&auth.Token{AccessToken: "abc"})
+			}
+			cache := NewLRUCache(10)
+			c.Cache = cache
+			c.CacheTTL = map[EndpointKind]time.Duration{
+				EndpointDetails:     time.Hour,
+				EndpointUserList:    time.Hour,
+				EndpointSuggestions: time.Hour,
+				EndpointUser:        time.Hour,
+			}
+
+			for n := 0; n < 2; n++ {
+				if err := tc.call(c); err != nil {
+					t.Fatalf("Unexpected error: %q", err)
+				}
+			}
+			if cached := calls == 1; cached != tc.cached {
+				t.Errorf("Expected cached %t, got %d calls", tc.cached, calls)
+			}
+			if tc.cached != (cache.Len() == 1) {
+				t.Errorf("Expected cached %t, got %d entries", tc.cached, cache.Len())
+			}
+		})
+	}
+}
+
+func TestListWritesEvictCache(t *testing.T) {
+	testCases := []struct {
+		name  string
+		write func(c *Client) error
+	}{
+		{"update", func(c *Client) error {
+			_, err := c.UpdateMyListStatus(21, &ListStatusUpdate{Score: Int(7)})
+			return err
+		}},
+		{"delete", func(c *Client) error { return c.DeleteMyListItem(21) }},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("Test case %d (%s)", i, tc.name), func(t *testing.T) {
+			c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, `{"id":21,"data":[]}`)
+			})
+			dc, err := NewDiskCache(t.TempDir())
+			if err != nil {
+				t.Fatalf("Unexpected error: %q", err)
+			}
+			c.Cache = dc
+			c.CacheTTL = map[EndpointKind]time.Duration{EndpointDetails: time.Hour, EndpointUserList: time.Hour}
+
+			// Fill the cache before authenticating, as user scoped responses aren't cached for authenticated clients
+			for _, id := range []int{21, 121} {
+				if _, err := c.GetDetails(&DetailsQuery{Id: id}); err != nil {
+					t.Fatalf("Unexpected error: %q", err)
+				}
+			}
+			if _, err := c.GetUserAnimeList(&UserAnimeListQuery{UserName: "a"}); err != nil {
+				t.Fatalf("Unexpected error: %q", err)
+			}
+			c.TokenSource = auth.StaticTokenSource(&auth.Token{AccessToken: "abc"})
+
+			if err := tc.write(c); err != nil {
+				t.Fatalf("Unexpected error: %q", err)
+			}
+			keys := dc.Keys()
+			if len(keys) != 1 || !strings.Contains(keys[0], "/anime/121") {
+				t.Errorf("Expected only the details of another anime to be left, got %v", keys)
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package malgomate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestContextCancellation(t *testing.T) {
+	testCases := []struct {
+		name string
+		call func(ctx context.Context, c *Client) error
+	}{
+		{"details", func(ctx context.Context, c *Client) error {
+			_, err := c.GetDetailsContext(ctx, &DetailsQuery{Id: 1})
+			return err
+		}},
+		{"anime", func(ctx context.Context, c *Client) error {
+			_, err := c.GetAnimeContext(ctx, &AnimeQuery{Query: "naruto"})
+			return err
+		}},
+		{"ranking", func(ctx context.Context, c *Client) error {
+			_, err := c.GetRankingContext(ctx, &RankingQuery{})
+			return err
+		}},
+		{"season", func(ctx context.Context, c *Client) error {
+			_, err := c.GetSeasonContext(ctx, &SeasonalQuery{Year: 2022, Season: SeasonWinter})
+			return err
+		}},
+		{"list qs", func(ctx context.Context, c *Client) error {
+			_, err := c.GetListQSContext(ctx, c.BaseURL+"/anime?q=naruto")
+			return err
+		}},
+		{"ranking qs", func(ctx context.Context, c *Client) error {
+			_, err := c.GetRankingQSContext(ctx, c.BaseURL+"/anime/ranking")
+			return err
+		}},
+		{"next page", func(ctx context.Context, c *Client) error {
+			return c.GetNextPageContext(ctx, &Paging{Next: c.BaseURL + "/anime?offset=100"}, &ListPage{})
+		}},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("Test case %d (%s)", i, tc.name), func(t *testing.T) {
+			aborted := make(chan struct{})
+			c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				select {
+				case <-r.Context().Done():
+					close(aborted)
+				case <-time.After(5 * time.Second):
+				}
+			})
+
+			ctx, cancel := context.WithCancel(context.Background())
+			time.AfterFunc(50*time.Millisecond, cancel)
+
+			if err := tc.call(ctx, c); !errors.Is(err, context.Canceled) {
+				t.Fatalf("Expected context.Canceled, got %v", err)
+			}
+
+			select {
+			case <-aborted:
+			case <-time.After(2 * time.Second):
+				t.Errorf("Expected the in-flight request to be aborted")
+			}
+		})
+	}
+}
+
+func TestContextDeadline(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	if _, err := c.GetDetailsContext(ctx, &DetailsQuery{Id: 1}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected context.DeadlineExceeded, got %v", err)
+	}
+}
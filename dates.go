@@ -0,0 +1,198 @@
+package malgomate
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DatePrecision is how much of a PartialDate is known
+type DatePrecision int
+
+// DatePrecision values, from least to most precise
+const (
+	PrecisionNone DatePrecision = iota
+	PrecisionYear
+	PrecisionMonth
+	PrecisionDay
+)
+
+// TimestampLayout is the layout MAL uses for timestamps such as created_at and updated_at
+const TimestampLayout = "2006-01-02T15:04:05-07:00"
+
+// PartialDate is a date that MAL may only know the year, or year and month of. Dates are sent as "2006",
+// "2006-04" or "2006-04-12", and the precision records which of those forms was used so it can be sent
+// back out in the same way. Dates in any other form are kept as is rather than failing to decode, and are
+// treated as unknown.
+type PartialDate struct {
+	Year      int
+	Month     time.Month
+	Day       int
+	Precision DatePrecision
+
+	// raw holds a date that couldn't be parsed, so that it can be sent back out unchanged
+	raw string
+}
+
+// ParsePartialDate parses a date in any of the formats returned by MAL. An empty string parses to the
+// zero PartialDate.
+func ParsePartialDate(str string) (PartialDate, error) {
+	if str == "" {
+		return PartialDate{}, nil
+	}
+
+	var layout string
+	precision := DatePrecision(strings.Count(str, "-") + 1)
+	switch precision {
+	case PrecisionYear:
+		layout = "2006"
+	case PrecisionMonth:
+		layout = "2006-01"
+	case PrecisionDay:
+		layout = "2006-01-02"
+	default:
+		return PartialDate{}, fmt.Errorf("invalid date %q", str)
+	}
+
+	t, err := time.Parse(layout, str)
+	if err != nil {
+		return PartialDate{}, fmt.Errorf("invalid date %q: %w", str, err)
+	}
+
+	d := PartialDate{Year: t.Year(), Precision: precision}
+	if precision >= PrecisionMonth {
+		d.Month = t.Month()
+	}
+	if precision == PrecisionDay {
+		d.Day = t.Day()
+	}
+	return d, nil
+}
+
+// IsZero reports whether the date is unknown
+func (d PartialDate) IsZero() bool {
+	return d.Precision == PrecisionNone
+}
+
+// String formats the date the same way MAL does, only including the known parts. Dates that couldn't be parsed
+// are returned as MAL sent them.
+func (d PartialDate) String() string {
+	switch d.Precision {
+	case PrecisionYear:
+		return fmt.Sprintf("%04d", d.Year)
+	case PrecisionMonth:
+		return fmt.Sprintf("%04d-%02d", d.Year, d.Month)
+	case PrecisionDay:
+		return fmt.Sprintf("%04d-%02d-%02d", d.Year, d.Month, d.Day)
+	}
+	return d.raw
+}
+
+// Time converts the date into the first instant it covers in the provided location, so "2006" becomes midnight
+// on January 1st 2006. Locations default to UTC when nil. The zero PartialDate converts to the zero time.Time.
+func (d PartialDate) Time(loc *time.Location) time.Time {
+	if d.IsZero() {
+		return time.Time{}
+	}
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	month, day := d.Month, d.Day
+	if d.Precision < PrecisionMonth {
+		month = time.January
+	}
+	if d.Precision < PrecisionDay {
+		day = 1
+	}
+	return time.Date(d.Year, month, day, 0, 0, 0, 0, loc)
+}
+
+// Compare returns -1, 0 or 1 depending on whether d comes before, is the same as, or comes after o. Dates
+// are compared part by part, and a less precise date comes before a more precise one that it covers, so
+// "2006" < "2006-04" < "2006-04-12". Unknown dates come before all others.
+func (d PartialDate) Compare(o PartialDate) int {
+	switch {
+	case d.IsZero() && o.IsZero():
+		return 0
+	case d.IsZero():
+		return -1
+	case o.IsZero():
+		return 1
+	}
+
+	for _, p := range [][2]int{{d.Year, o.Year}, {int(d.Month), int(o.Month)}, {d.Day, o.Day}} {
+		if p[0] < p[1] {
+			return -1
+		}
+		if p[0] > p[1] {
+			return 1
+		}
+	}
+	return 0
+}
+
+// Before reports whether d comes before o
+func (d PartialDate) Before(o PartialDate) bool {
+	return d.Compare(o) < 0
+}
+
+// After reports whether d comes after o
+func (d PartialDate) After(o PartialDate) bool {
+	return d.Compare(o) > 0
+}
+
+// MarshalJSON implements json.Marshaler, using the same format as MAL
+func (d PartialDate) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+// UnmarshalJSON implements json.Unmarshaler. Dates that can't be parsed are kept as unknown dates, rather than
+// failing the whole response.
+func (d *PartialDate) UnmarshalJSON(b []byte) error {
+	var str string
+	if err := json.Unmarshal(b, &str); err != nil {
+		return err
+	}
+	pd, err := ParsePartialDate(str)
+	if err != nil {
+		pd = PartialDate{raw: str}
+	}
+	*d = pd
+	return nil
+}
+
+// Timestamp is a point in time sent by MAL, such as when an entry was last updated. It is sent back out
+// using TimestampLayout, keeping the original offset, so a "Z" offset is sent back out as "+00:00" and
+// fractional seconds are dropped.
+type Timestamp struct {
+	time.Time
+}
+
+// MarshalJSON implements json.Marshaler, using the same format as MAL
+func (t Timestamp) MarshalJSON() ([]byte, error) {
+	if t.IsZero() {
+		return json.Marshal("")
+	}
+	return json.Marshal(t.Format(TimestampLayout))
+}
+
+// UnmarshalJSON implements json.Unmarshaler. Timestamps with fractional seconds or a "Z" offset are accepted
+// as well.
+func (t *Timestamp) UnmarshalJSON(b []byte) error {
+	var str string
+	if err := json.Unmarshal(b, &str); err != nil {
+		return err
+	}
+	if str == "" {
+		*t = Timestamp{}
+		return nil
+	}
+	parsed, err := time.Parse(time.RFC3339Nano, str)
+	if err != nil {
+		return fmt.Errorf("invalid timestamp %q: %w", str, err)
+	}
+	*t = Timestamp{parsed}
+	return nil
+}
@@ -0,0 +1,134 @@
+package malgomate
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestParsePartialDate(t *testing.T) {
+	testCases := []struct {
+		in       string
+		expected PartialDate
+		err      bool
+	}{
+		{"", PartialDate{}, false},
+		{"2006", PartialDate{Year: 2006, Precision: PrecisionYear}, false},
+		{"2006-04", PartialDate{Year: 2006, Month: time.April, Precision: PrecisionMonth}, false},
+		{"2006-04-12", PartialDate{Year: 2006, Month: time.April, Day: 12, Precision: PrecisionDay}, false},
+		{"2006-13", PartialDate{}, true},
+		{"April 2006", PartialDate{}, true},
+		{"2006-04-12-01", PartialDate{}, true},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("Test case %d", i), func(t *testing.T) {
+			got, err := ParsePartialDate(tc.in)
+			if (err != nil) != tc.err {
+				t.Fatalf("Expected error %t, got %v", tc.err, err)
+			}
+			if got != tc.expected {
+				t.Errorf("Expected %+v, got %+v", tc.expected, got)
+			}
+			if !tc.err && got.String() != tc.in {
+				t.Errorf("Expected %q, got %q", tc.in, got.String())
+			}
+		})
+	}
+}
+
+func TestPartialDateCompare(t *testing.T) {
+	testCases := []struct {
+		a, b     string
+		expected int
+	}{
+		{"2006", "2006", 0},
+		{"2006", "2007", -1},
+		{"2006", "2006-04", -1},
+		{"2006-04", "2006-04-12", -1},
+		{"2006-05", "2006-04-12", 1},
+		{"2006-04-12", "2006-04-12", 0},
+		{"", "1917", -1},
+		{"", "", 0},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("Test case %d", i), func(t *testing.T) {
+			a, _ := ParsePartialDate(tc.a)
+			b, _ := ParsePartialDate(tc.b)
+			if got := a.Compare(b); got != tc.expected {
+				t.Errorf("Expected %d, got %d", tc.expected, got)
+			}
+			if got := b.Compare(a); got != -tc.expected {
+				t.Errorf("Expected reversed %d, got %d", -tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestPartialDateTime(t *testing.T) {
+	jst := time.FixedZone("JST", 9*60*60)
+	testCases := []struct {
+		in       string
+		loc      *time.Location
+		expected time.Time
+	}{
+		{"2006", nil, time.Date(2006, time.January, 1, 0, 0, 0, 0, time.UTC)},
+		{"2006-04", nil, time.Date(2006, time.April, 1, 0, 0, 0, 0, time.UTC)},
+		{"2006-04-12", jst, time.Date(2006, time.April, 12, 0, 0, 0, 0, jst)},
+		{"", nil, time.Time{}},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("Test case %d", i), func(t *testing.T) {
+			d, _ := ParsePartialDate(tc.in)
+			if got := d.Time(tc.loc); !got.Equal(tc.expected) {
+				t.Errorf("Expected %s, got %s", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestDateRoundTrip(t *testing.T) {
+	testCases := []string{
+		`{"id":1,"title":"a","start_date":"2006","end_date":"2006-09","created_at":"2015-03-02T06:03:11+00:00","updated_at":"2022-01-02T12:04:05+09:00"}`,
+		`{"id":1,"title":"a","start_date":"2006-04-12"}`,
+		// Dates MAL sends in an unexpected form are kept rather than failing the response
+		`{"id":1,"title":"a","start_date":"2006-04-12","end_date":"sometime in 2006"}`,
+		`{"id":1,"title":"a"}`,
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("Test case %d", i), func(t *testing.T) {
+			var a Anime
+			if err := json.Unmarshal([]byte(tc), &a); err != nil {
+				t.Fatalf("Unexpected error: %q", err)
+			}
+			b, err := json.Marshal(a)
+			if err != nil {
+				t.Fatalf("Unexpected error: %q", err)
+			}
+			if string(b) != tc {
+				t.Errorf("Expected %s, got %s", tc, b)
+			}
+		})
+	}
+}
+
+func TestTimestampUnmarshal(t *testing.T) {
+	var a Anime
+	if err := json.Unmarshal([]byte(`{"created_at":"2015-03-02T06:03:11+00:00","updated_at":"2022-01-02T12:04:05.5+09:00"}`), &a); err != nil {
+		t.Fatalf("Unexpected error: %q", err)
+	}
+	if expected := time.Date(2015, time.March, 2, 6, 3, 11, 0, time.UTC); !a.CreatedAt.Equal(expected) {
+		t.Errorf("Expected %s, got %s", expected, a.CreatedAt)
+	}
+	if expected := time.Date(2022, time.January, 2, 3, 4, 5, 5e8, time.UTC); !a.UpdatedAt.Equal(expected) {
+		t.Errorf("Expected %s, got %s", expected, a.UpdatedAt)
+	}
+
+	if err := json.Unmarshal([]byte(`{"created_at":"yesterday"}`), &a); err == nil {
+		t.Errorf("Expected an error for an invalid timestamp")
+	}
+}
@@ -0,0 +1,98 @@
+package malgomate
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+)
+
+// DiskCache is a Cache that stores each entry as a file in a directory, so that cached responses survive
+// restarts. Entries are never evicted, only overwritten or deleted.
+type DiskCache struct {
+	dir string
+}
+
+// diskEntry is the format of each file written by the DiskCache
+type diskEntry struct {
+	Key string `json:"key"`
+	CacheEntry
+}
+
+// NewDiskCache is a constructor for a DiskCache that stores its entries in dir, creating it if needed
+func NewDiskCache(dir string) (*DiskCache, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &DiskCache{dir: dir}, nil
+}
+
+// path works out the file used to store a key
+func (dc *DiskCache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(dc.dir, hex.EncodeToString(sum[:])+".json")
+}
+
+// Get reads an entry from disk. Unreadable entries are treated as missing.
+func (dc *DiskCache) Get(key string) (*CacheEntry, bool) {
+	b, err := os.ReadFile(dc.path(key))
+	if err != nil {
+		return nil, false
+	}
+	var de diskEntry
+	if err := json.Unmarshal(b, &de); err != nil || de.Key != key {
+		return nil, false
+	}
+	return &de.CacheEntry, true
+}
+
+// Set writes an entry to disk. The entry is written to a temporary file first and then moved into place, so
+// readers never see a partially written entry. Failed writes are dropped, as the entry can always be refetched.
+func (dc *DiskCache) Set(key string, entry *CacheEntry) {
+	b, err := json.Marshal(diskEntry{Key: key, CacheEntry: *entry})
+	if err != nil {
+		return
+	}
+	tmp, err := os.CreateTemp(dc.dir, "entry-*")
+	if err != nil {
+		return
+	}
+	_, err = tmp.Write(b)
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return
+	}
+	if err := os.Rename(tmp.Name(), dc.path(key)); err != nil {
+		os.Remove(tmp.Name())
+	}
+}
+
+// Delete removes an entry from disk
+func (dc *DiskCache) Delete(key string) {
+	os.Remove(dc.path(key))
+}
+
+// Keys lists the keys of every entry on disk. Unreadable entries are skipped.
+func (dc *DiskCache) Keys() []string {
+	paths, err := filepath.Glob(filepath.Join(dc.dir, "*.json"))
+	if err != nil {
+		return nil
+	}
+	var keys []string
+	for _, p := range paths {
+		b, err := os.ReadFile(p)
+		if err != nil {
+			continue
+		}
+		var de diskEntry
+		if err := json.Unmarshal(b, &de); err != nil || de.Key == "" {
+			continue
+		}
+		keys = append(keys, de.Key)
+	}
+	return keys
+}
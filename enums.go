@@ -0,0 +1,406 @@
+package malgomate
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+)
+
+// MediaType is the format an anime was released in
+type MediaType string
+
+// AiringStatus is the airing state of an anime
+type AiringStatus string
+
+// SourceType is the original work an anime was adapted from
+type SourceType string
+
+// Rating is the age rating of an anime
+type Rating string
+
+// Nsfw is MAL's rating of whether a work is safe for work
+type Nsfw string
+
+// MangaMediaType is the format a manga was published in
+type MangaMediaType string
+
+// PublishingStatus is the publishing state of a manga
+type PublishingStatus string
+
+// MediaType values returned by MAL
+const (
+	MediaTypeUnknown   MediaType = "unknown"
+	MediaTypeTv        MediaType = "tv"
+	MediaTypeOva       MediaType = "ova"
+	MediaTypeMovie     MediaType = "movie"
+	MediaTypeSpecial   MediaType = "special"
+	MediaTypeOna       MediaType = "ona"
+	MediaTypeMusic     MediaType = "music"
+	MediaTypeTvSpecial MediaType = "tv_special"
+	MediaTypePv        MediaType = "pv"
+	MediaTypeCm        MediaType = "cm"
+)
+
+// AiringStatus values returned by MAL
+const (
+	AiringStatusFinished        AiringStatus = "finished_airing"
+	AiringStatusCurrentlyAiring AiringStatus = "currently_airing"
+	AiringStatusNotYetAired     AiringStatus = "not_yet_aired"
+)
+
+// SourceType values returned by MAL
+const (
+	SourceOther        SourceType = "other"
+	SourceOriginal     SourceType = "original"
+	SourceManga        SourceType = "manga"
+	Source4KomaManga   SourceType = "4_koma_manga"
+	SourceWebManga     SourceType = "web_manga"
+	SourceDigitalManga SourceType = "digital_manga"
+	SourceNovel        SourceType = "novel"
+	SourceLightNovel   SourceType = "light_novel"
+	SourceVisualNovel  SourceType = "visual_novel"
+	SourceGame         SourceType = "game"
+	SourceCardGame     SourceType = "card_game"
+	SourceBook         SourceType = "book"
+	SourcePictureBook  SourceType = "picture_book"
+	SourceRadio        SourceType = "radio"
+	SourceMusic        SourceType = "music"
+	SourceWebNovel     SourceType = "web_novel"
+	SourceMixedMedia   SourceType = "mixed_media"
+)
+
+// Rating values returned by MAL
+const (
+	RatingG     Rating = "g"
+	RatingPg    Rating = "pg"
+	RatingPg13  Rating = "pg_13"
+	RatingR     Rating = "r"
+	RatingRPlus Rating = "r+"
+	RatingRx    Rating = "rx"
+)
+
+// Nsfw values returned by MAL
+const (
+	NsfwWhite Nsfw = "white"
+	NsfwGray  Nsfw = "gray"
+	NsfwBlack Nsfw = "black"
+)
+
+// MangaMediaType values returned by MAL
+const (
+	MangaMediaTypeUnknown    MangaMediaType = "unknown"
+	MangaMediaTypeManga      MangaMediaType = "manga"
+	MangaMediaTypeNovel      MangaMediaType = "novel"
+	MangaMediaTypeOneShot    MangaMediaType = "one_shot"
+	MangaMediaTypeDoujinshi  MangaMediaType = "doujinshi"
+	MangaMediaTypeManhwa     MangaMediaType = "manhwa"
+	MangaMediaTypeManhua     MangaMediaType = "manhua"
+	MangaMediaTypeOel        MangaMediaType = "oel"
+	MangaMediaTypeLightNovel MangaMediaType = "light_novel"
+)
+
+// PublishingStatus values returned by MAL
+const (
+	PublishingStatusFinished            PublishingStatus = "finished"
+	PublishingStatusCurrentlyPublishing PublishingStatus = "currently_publishing"
+	PublishingStatusNotYetPublished     PublishingStatus = "not_yet_published"
+	PublishingStatusOnHiatus            PublishingStatus = "on_hiatus"
+	PublishingStatusDiscontinued        PublishingStatus = "discontinued"
+)
+
+// MediaTypes are a collection of MediaType
+type MediaTypes []MediaType
+
+// IsValid checks to see if the supplied value is a valid MediaType
+func (mt MediaTypes) IsValid(str string) bool {
+	converted := MediaType(str)
+	for _, v := range mt {
+		if v == converted {
+			return true
+		}
+	}
+	return false
+}
+
+// AiringStatusTypes are a collection of AiringStatus
+type AiringStatusTypes []AiringStatus
+
+// IsValid checks to see if the supplied value is a valid AiringStatus
+func (ast AiringStatusTypes) IsValid(str string) bool {
+	converted := AiringStatus(str)
+	for _, v := range ast {
+		if v == converted {
+			return true
+		}
+	}
+	return false
+}
+
+// SourceTypes are a collection of SourceType
+type SourceTypes []SourceType
+
+// IsValid checks to see if the supplied value is a valid SourceType
+func (st SourceTypes) IsValid(str string) bool {
+	converted := SourceType(str)
+	for _, v := range st {
+		if v == converted {
+			return true
+		}
+	}
+	return false
+}
+
+// RatingTypes are a collection of Rating
+type RatingTypes []Rating
+
+// IsValid checks to see if the supplied value is a valid Rating
+func (rt RatingTypes) IsValid(str string) bool {
+	converted := Rating(str)
+	for _, v := range rt {
+		if v == converted {
+			return true
+		}
+	}
+	return false
+}
+
+// NsfwTypes are a collection of Nsfw
+type NsfwTypes []Nsfw
+
+// IsValid checks to see if the supplied value is a valid Nsfw
+func (nt NsfwTypes) IsValid(str string) bool {
+	converted := Nsfw(str)
+	for _, v := range nt {
+		if v == converted {
+			return true
+		}
+	}
+	return false
+}
+
+// MangaMediaTypes are a collection of MangaMediaType
+type MangaMediaTypes []MangaMediaType
+
+// IsValid checks to see if the supplied value is a valid MangaMediaType
+func (mmt MangaMediaTypes) IsValid(str string) bool {
+	converted := MangaMediaType(str)
+	for _, v := range mmt {
+		if v == converted {
+			return true
+		}
+	}
+	return false
+}
+
+// PublishingStatusTypes are a collection of PublishingStatus
+type PublishingStatusTypes []PublishingStatus
+
+// IsValid checks to see if the supplied value is a valid PublishingStatus
+func (pst PublishingStatusTypes) IsValid(str string) bool {
+	converted := PublishingStatus(str)
+	for _, v := range pst {
+		if v == converted {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	// MediaTypeQueries are the media types MAL documents for anime
+	MediaTypeQueries MediaTypes = []MediaType{
+		MediaTypeUnknown,
+		MediaTypeTv,
+		MediaTypeOva,
+		MediaTypeMovie,
+		MediaTypeSpecial,
+		MediaTypeOna,
+		MediaTypeMusic,
+		MediaTypeTvSpecial,
+		MediaTypePv,
+		MediaTypeCm,
+	}
+
+	// AiringStatusQueries are the airing statuses MAL documents for anime
+	AiringStatusQueries AiringStatusTypes = []AiringStatus{
+		AiringStatusFinished,
+		AiringStatusCurrentlyAiring,
+		AiringStatusNotYetAired,
+	}
+
+	// SourceTypeQueries are the sources MAL documents for anime
+	SourceTypeQueries SourceTypes = []SourceType{
+		SourceOther,
+		SourceOriginal,
+		SourceManga,
+		Source4KomaManga,
+		SourceWebManga,
+		SourceDigitalManga,
+		SourceNovel,
+		SourceLightNovel,
+		SourceVisualNovel,
+		SourceGame,
+		SourceCardGame,
+		SourceBook,
+		SourcePictureBook,
+		SourceRadio,
+		SourceMusic,
+		SourceWebNovel,
+		SourceMixedMedia,
+	}
+
+	// RatingQueries are the age ratings MAL documents for anime
+	RatingQueries RatingTypes = []Rating{
+		RatingG,
+		RatingPg,
+		RatingPg13,
+		RatingR,
+		RatingRPlus,
+		RatingRx,
+	}
+
+	// NsfwQueries are the nsfw ratings MAL documents for anime and manga
+	NsfwQueries NsfwTypes = []Nsfw{
+		NsfwWhite,
+		NsfwGray,
+		NsfwBlack,
+	}
+
+	// MangaMediaTypeQueries are the media types MAL documents for manga
+	MangaMediaTypeQueries MangaMediaTypes = []MangaMediaType{
+		MangaMediaTypeUnknown,
+		MangaMediaTypeManga,
+		MangaMediaTypeNovel,
+		MangaMediaTypeOneShot,
+		MangaMediaTypeDoujinshi,
+		MangaMediaTypeManhwa,
+		MangaMediaTypeManhua,
+		MangaMediaTypeOel,
+		MangaMediaTypeLightNovel,
+	}
+
+	// PublishingStatusQueries are the publishing statuses MAL documents for manga
+	PublishingStatusQueries PublishingStatusTypes = []PublishingStatus{
+		PublishingStatusFinished,
+		PublishingStatusCurrentlyPublishing,
+		PublishingStatusNotYetPublished,
+		PublishingStatusOnHiatus,
+		PublishingStatusDiscontinued,
+	}
+)
+
+// displayNames are the human readable names of values that can't be worked out from the value itself
+var displayNames = map[string]string{
+	string(MediaTypeTv):        "TV",
+	string(MediaTypeOva):       "OVA",
+	string(MediaTypeOna):       "ONA",
+	string(MediaTypeTvSpecial): "TV Special",
+	string(MediaTypePv):        "PV",
+	string(MediaTypeCm):        "CM",
+
+	string(Source4KomaManga): "4-koma Manga",
+
+	string(RatingG):     "G",
+	string(RatingPg):    "PG",
+	string(RatingPg13):  "PG-13",
+	string(RatingR):     "R",
+	string(RatingRPlus): "R+",
+	string(RatingRx):    "Rx",
+
+	string(NsfwWhite): "Safe for Work",
+	string(NsfwGray):  "Possibly Not Safe for Work",
+	string(NsfwBlack): "Not Safe for Work",
+
+	string(MangaMediaTypeOneShot): "One-shot",
+	string(MangaMediaTypeOel):     "OEL",
+}
+
+// display converts a value into a human readable string. Values without a display name are title cased
+// with their underscores replaced, so values MAL adds later still display sensibly.
+func display(str string) string {
+	if name, ok := displayNames[str]; ok {
+		return name
+	}
+	words := strings.Fields(strings.ReplaceAll(str, "_", " "))
+	for i, w := range words {
+		words[i] = strings.ToUpper(w[:1]) + w[1:]
+	}
+	return strings.Join(words, " ")
+}
+
+// Display returns the human readable name of the media type, e.g. "TV"
+func (mt MediaType) Display() string { return display(string(mt)) }
+
+// Display returns the human readable name of the airing status, e.g. "Currently Airing"
+func (as AiringStatus) Display() string { return display(string(as)) }
+
+// Display returns the human readable name of the source, e.g. "Light Novel"
+func (st SourceType) Display() string { return display(string(st)) }
+
+// Display returns the human readable name of the rating, e.g. "PG-13"
+func (r Rating) Display() string { return display(string(r)) }
+
+// Display returns the human readable name of the nsfw rating, e.g. "Safe for Work"
+func (n Nsfw) Display() string { return display(string(n)) }
+
+// Display returns the human readable name of the media type, e.g. "Light Novel"
+func (mmt MangaMediaType) Display() string { return display(string(mmt)) }
+
+// Display returns the human readable name of the publishing status, e.g. "On Hiatus"
+func (ps PublishingStatus) Display() string { return display(string(ps)) }
+
+// unmarshalEnum decodes an enum value without ever failing on it. Values outside of the documented set are
+// kept as is, nulls are left empty and anything that isn't a string is kept as its raw JSON text, so a change
+// on MAL's side never breaks decoding of the whole response.
+func unmarshalEnum(b []byte) string {
+	var str string
+	if err := json.Unmarshal(b, &str); err == nil {
+		return str
+	}
+	if b = bytes.TrimSpace(b); bytes.Equal(b, []byte("null")) {
+		return ""
+	}
+	return string(b)
+}
+
+// UnmarshalJSON implements json.Unmarshaler, preserving unknown values
+func (mt *MediaType) UnmarshalJSON(b []byte) error {
+	*mt = MediaType(unmarshalEnum(b))
+	return nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler, preserving unknown values
+func (as *AiringStatus) UnmarshalJSON(b []byte) error {
+	*as = AiringStatus(unmarshalEnum(b))
+	return nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler, preserving unknown values
+func (st *SourceType) UnmarshalJSON(b []byte) error {
+	*st = SourceType(unmarshalEnum(b))
+	return nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler, preserving unknown values
+func (r *Rating) UnmarshalJSON(b []byte) error {
+	*r = Rating(unmarshalEnum(b))
+	return nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler, preserving unknown values
+func (n *Nsfw) UnmarshalJSON(b []byte) error {
+	*n = Nsfw(unmarshalEnum(b))
+	return nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler, preserving unknown values
+func (mmt *MangaMediaType) UnmarshalJSON(b []byte) error {
+	*mmt = MangaMediaType(unmarshalEnum(b))
+	return nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler, preserving unknown values
+func (ps *PublishingStatus) UnmarshalJSON(b []byte) error {
+	*ps = PublishingStatus(unmarshalEnum(b))
+	return nil
+}
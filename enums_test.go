@@ -0,0 +1,90 @@
+package malgomate
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+)
+
+func TestEnumIsValid(t *testing.T) {
+	testCases := []struct {
+		valid    func(string) bool
+		in       string
+		expected bool
+	}{
+		{MediaTypeQueries.IsValid, "tv", true},
+		{MediaTypeQueries.IsValid, "TV", false},
+		{AiringStatusQueries.IsValid, "currently_airing", true},
+		{AiringStatusQueries.IsValid, "airing", false},
+		{SourceTypeQueries.IsValid, "4_koma_manga", true},
+		{SourceTypeQueries.IsValid, "comic", false},
+		{RatingQueries.IsValid, "pg_13", true},
+		{RatingQueries.IsValid, "pg13", false},
+		{NsfwQueries.IsValid, "gray", true},
+		{NsfwQueries.IsValid, "grey", false},
+		{MangaMediaTypeQueries.IsValid, "one_shot", true},
+		{PublishingStatusQueries.IsValid, "on_hiatus", true},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("Test case %d", i), func(t *testing.T) {
+			if got := tc.valid(tc.in); got != tc.expected {
+				t.Errorf("Expected %t, got %t", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestEnumDisplay(t *testing.T) {
+	testCases := []struct {
+		in       interface{ Display() string }
+		expected string
+	}{
+		{MediaTypeTv, "TV"},
+		{MediaTypeTvSpecial, "TV Special"},
+		{MediaTypeMovie, "Movie"},
+		{AiringStatusCurrentlyAiring, "Currently Airing"},
+		{Source4KomaManga, "4-koma Manga"},
+		{SourceLightNovel, "Light Novel"},
+		{RatingPg13, "PG-13"},
+		{RatingRPlus, "R+"},
+		{NsfwWhite, "Safe for Work"},
+		{MangaMediaTypeOneShot, "One-shot"},
+		{PublishingStatusOnHiatus, "On Hiatus"},
+		{MediaType("some_new_type"), "Some New Type"},
+		{Rating(""), ""},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("Test case %d", i), func(t *testing.T) {
+			if got := tc.in.Display(); got != tc.expected {
+				t.Errorf("Expected %q, got %q", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestEnumUnmarshal(t *testing.T) {
+	testCases := []struct {
+		in       string
+		expected Anime
+	}{
+		{`{"media_type":"tv","status":"finished_airing","source":"manga","rating":"pg_13","nsfw":"white"}`,
+			Anime{MediaType: MediaTypeTv, Status: AiringStatusFinished, Source: SourceManga, Rating: RatingPg13, Nsfw: NsfwWhite}},
+		{`{"media_type":"hologram","rating":"pg_21"}`, Anime{MediaType: "hologram", Rating: "pg_21"}},
+		{`{"media_type":null,"source":7}`, Anime{Source: "7"}},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("Test case %d", i), func(t *testing.T) {
+			var a Anime
+			if err := json.Unmarshal([]byte(tc.in), &a); err != nil {
+				t.Fatalf("Unexpected error: %q", err)
+			}
+			if a.MediaType != tc.expected.MediaType || a.Status != tc.expected.Status || a.Source != tc.expected.Source ||
+				a.Rating != tc.expected.Rating || a.Nsfw != tc.expected.Nsfw {
+				t.Errorf("Expected %+v, got %+v", tc.expected, a)
+			}
+		})
+	}
+}
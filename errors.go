@@ -0,0 +1,114 @@
+package malgomate
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// Sentinel errors that can be matched against using errors.Is
+var (
+	ErrNotFound     = errors.New("resource not found")
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrRateLimited  = errors.New("rate limited")
+	ErrServerError  = errors.New("server error")
+
+	// ErrAuthRequired is returned when calling a user scoped endpoint with a client that only has a client ID
+	ErrAuthRequired = errors.New("endpoint requires a client authenticated with a bearer token")
+
+	// ErrNotInList is returned when deleting an anime that is not on the authenticated user's list
+	ErrNotInList = errors.New("anime is not on the user's list")
+
+	// ErrNoBroadcast is returned by the broadcast helpers when an anime doesn't have a regular weekly broadcast,
+	// or is missing the fields needed to work out its schedule
+	ErrNoBroadcast = errors.New("anime has no regular weekly broadcast")
+
+	// ErrInvalidField is returned when requesting a field from an endpoint that doesn't support it
+	ErrInvalidField = errors.New("invalid field")
+)
+
+// errorResponse is a general eror wrapper
+type errorResponse struct {
+	Error   string `json:"error"`
+	Message string `json:"message"`
+}
+
+// APIError is returned whenever the MAL API responds with an error status code. It holds on to everything
+// MAL sent back so that callers can inspect the failure, or match it against one of the sentinel errors
+// (ErrNotFound, ErrUnauthorized, etc.) using errors.Is.
+type APIError struct {
+	StatusCode int
+	Code       string
+	Message    string
+	URL        string
+	Header     http.Header
+}
+
+// Error implements the error interface
+func (e *APIError) Error() string {
+	msg := e.Message
+	if msg == "" {
+		msg = e.Code
+	}
+	if msg == "" {
+		msg = "unknown error"
+	}
+	return fmt.Sprintf("%s (status code: %d)", msg, e.StatusCode)
+}
+
+// Is reports whether the APIError matches one of the sentinel errors. Both 401 and 403 responses are
+// treated as ErrUnauthorized, and any 5xx response is treated as ErrServerError.
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized || e.StatusCode == http.StatusForbidden
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests
+	case ErrServerError:
+		return e.StatusCode >= http.StatusInternalServerError
+	}
+	return false
+}
+
+// notInListError is returned when deleting an anime that isn't on the user's list. It matches ErrNotInList, while
+// still unwrapping to the *APIError MAL responded with.
+type notInListError struct {
+	id  int
+	err error
+}
+
+// Error implements the error interface
+func (e *notInListError) Error() string {
+	return fmt.Sprintf("%s: anime %d: %s", ErrNotInList, e.id, e.err)
+}
+
+// Is reports whether the error matches ErrNotInList
+func (e *notInListError) Is(target error) bool {
+	return target == ErrNotInList
+}
+
+// Unwrap returns the error MAL responded with
+func (e *notInListError) Unwrap() error {
+	return e.err
+}
+
+// newAPIError builds an APIError out of a failed response. The body is decoded on a best effort basis, as
+// not every error that comes back from MAL is guaranteed to have one.
+func newAPIError(req *http.Request, res *http.Response) *APIError {
+	apiErr := &APIError{
+		StatusCode: res.StatusCode,
+		URL:        req.URL.String(),
+		Header:     res.Header,
+	}
+
+	var errRes errorResponse
+	if err := json.NewDecoder(res.Body).Decode(&errRes); err == nil {
+		apiErr.Code = errRes.Error
+		apiErr.Message = errRes.Message
+	}
+
+	return apiErr
+}
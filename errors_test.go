@@ -0,0 +1,67 @@
+package malgomate
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// newTestClient spins up a local server using the provided handler and returns a client pointed at it
+func newTestClient(t *testing.T, h http.HandlerFunc) *Client {
+	t.Helper()
+	srv := httptest.NewServer(h)
+	t.Cleanup(srv.Close)
+	c := NewClient("test-key")
+	c.BaseURL = srv.URL
+	return c
+}
+
+func TestAPIError(t *testing.T) {
+	testCases := []struct {
+		status   int
+		body     string
+		sentinel error
+		code     string
+		message  string
+	}{
+		{http.StatusNotFound, `{"error":"not_found","message":""}`, ErrNotFound, "not_found", ""},
+		{http.StatusUnauthorized, `{"error":"invalid_token","message":"token is invalid"}`, ErrUnauthorized, "invalid_token", "token is invalid"},
+		{http.StatusForbidden, `{"error":"forbidden"}`, ErrUnauthorized, "forbidden", ""},
+		{http.StatusTooManyRequests, ``, ErrRateLimited, "", ""},
+		{http.StatusBadGateway, `<html>bad gateway</html>`, ErrServerError, "", ""},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("Test case %d", i), func(t *testing.T) {
+			c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("X-Test", "yes")
+				w.WriteHeader(tc.status)
+				fmt.Fprint(w, tc.body)
+			})
+
+			_, err := c.GetDetails(&DetailsQuery{Id: 1})
+			if !errors.Is(err, tc.sentinel) {
+				t.Fatalf("Expected error to match %q, got %v", tc.sentinel, err)
+			}
+
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("Expected an *APIError, got %T", err)
+			}
+			if apiErr.StatusCode != tc.status {
+				t.Errorf("Expected status %d, got %d", tc.status, apiErr.StatusCode)
+			}
+			if apiErr.Code != tc.code || apiErr.Message != tc.message {
+				t.Errorf("Expected code %q and message %q, got %q and %q", tc.code, tc.message, apiErr.Code, apiErr.Message)
+			}
+			if apiErr.Header.Get("X-Test") != "yes" {
+				t.Errorf("Expected response headers to be kept")
+			}
+			if apiErr.URL != c.BaseURL+"/anime/1?fields=id,title,main_picture" {
+				t.Errorf("Unexpected request URL %q", apiErr.URL)
+			}
+		})
+	}
+}
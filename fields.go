@@ -0,0 +1,276 @@
+package malgomate
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Field is the name of a field to be returned by MAL. The same fields are used by every endpoint, although not
+// every endpoint supports every field; see LookupField.
+type Field string
+
+// Fields are a collection of Field
+type Fields []Field
+
+// QueryField is the name of a field requested from a list, ranking or seasonal query. It is an alias of Field.
+type QueryField = Field
+
+// QueryFields are a collection of QueryField. It is an alias of Fields.
+type QueryFields = Fields
+
+// DetailField is the name of a field requested from a details query. It is an alias of Field.
+type DetailField = Field
+
+// DetailFields are a collection of DetailField. It is an alias of Fields.
+type DetailFields = Fields
+
+// SubFields attaches a sub query onto a Field. Sub fields can have sub fields of their own, to any depth:
+//
+//	FieldRelatedAnime.SubFields(&Fields{
+//	    FieldRank,
+//	    FieldAlternativeTitles.SubFields(&Fields{"en"}),
+//	})
+//
+// The field is returned as is when no sub fields are given.
+func (f Field) SubFields(sq *Fields) Field {
+	if sq == nil || len(*sq) == 0 {
+		return f
+	}
+	str := sq.ToString()
+	return Field(fmt.Sprintf("%s{%s}", f, str))
+}
+
+// Name is the field name, without any sub fields attached
+func (f Field) Name() Field {
+	if i := strings.IndexByte(string(f), '{'); i >= 0 {
+		return f[:i]
+	}
+	return f
+}
+
+// parse splits a field into its name and its sub fields, checking that its braces are balanced
+func (f Field) parse() (Field, Fields, error) {
+	i := strings.IndexByte(string(f), '{')
+	if i < 0 {
+		if strings.ContainsAny(string(f), "},") {
+			return "", nil, fmt.Errorf("%w: malformed field %q", ErrInvalidField, f)
+		}
+		return f, nil, nil
+	}
+
+	// The opening brace must only be closed by the final character
+	depth := 0
+	for j, r := range f[i:] {
+		switch r {
+		case '{':
+			depth++
+		case '}':
+			depth--
+		}
+		if depth == 0 && i+j != len(f)-1 {
+			return "", nil, fmt.Errorf("%w: malformed field %q", ErrInvalidField, f)
+		}
+	}
+	if depth != 0 || i == 0 {
+		return "", nil, fmt.Errorf("%w: malformed field %q", ErrInvalidField, f)
+	}
+
+	var sub Fields
+	for _, s := range splitFields(string(f[i+1 : len(f)-1])) {
+		if s == "" {
+			return "", nil, fmt.Errorf("%w: malformed field %q", ErrInvalidField, f)
+		}
+		sub = append(sub, Field(s))
+	}
+	return f[:i], sub, nil
+}
+
+// ToString converts a slice of Field into a comma separate string of fields
+func (fs Fields) ToString() string {
+	var sb strings.Builder
+	for i, str := range fs {
+		sb.WriteString(string(str))
+		if i < len(fs)-1 {
+			sb.WriteString(",")
+		}
+	}
+	return sb.String()
+}
+
+// contains checks to see if the supplied field has been requested. Fields with sub fields attached are
+// matched on their name alone.
+func (fs Fields) contains(field Field) bool {
+	for _, f := range fs {
+		if f.Name() == field {
+			return true
+		}
+	}
+	return false
+}
+
+// Field values are every field supported by MAL, across all endpoints. See LookupField for which endpoints
+// each one can be requested from.
+const (
+	FieldID                     Field = "id"
+	FieldTitle                  Field = "title"
+	FieldMainPicture            Field = "main_picture"
+	FieldAlternativeTitles      Field = "alternative_titles"
+	FieldStartDate              Field = "start_date"
+	FieldEndDate                Field = "end_date"
+	FieldSynopsis               Field = "synopsis"
+	FieldMean                   Field = "mean"
+	FieldRank                   Field = "rank"
+	FieldPopularity             Field = "popularity"
+	FieldNumListUsers           Field = "num_list_users"
+	FieldNumScoringUsers        Field = "num_scoring_users"
+	FieldNsfw                   Field = "nsfw"
+	FieldGenres                 Field = "genres"
+	FieldCreatedAt              Field = "created_at"
+	FieldUpdatedAt              Field = "updated_at"
+	FieldMediaType              Field = "media_type"
+	FieldStatus                 Field = "status"
+	FieldNumEpisodes            Field = "num_episodes"
+	FieldStartSeason            Field = "start_season"
+	FieldBroadcast              Field = "broadcast"
+	FieldSource                 Field = "source"
+	FieldAverageEpisodeDuration Field = "average_episode_duration"
+	FieldRating                 Field = "rating"
+	FieldPictures               Field = "pictures"
+	FieldBackground             Field = "background"
+	FieldRelatedAnime           Field = "related_anime"
+	FieldRelatedManga           Field = "related_manga"
+	FieldRecommendations        Field = "recommendations"
+	FieldStudios                Field = "studios"
+	FieldStatistics             Field = "statistics"
+	FieldListStatus             Field = "list_status"
+	FieldMyListStatus           Field = "my_list_status"
+	FieldNumVolumes             Field = "num_volumes"
+	FieldNumChapters            Field = "num_chapters"
+	FieldAuthors                Field = "authors"
+	FieldSerialization          Field = "serialization"
+)
+
+// FieldInfo describes which endpoints a field can be requested from
+type FieldInfo struct {
+	// Endpoints are the kinds of endpoint that return the field
+	Endpoints []EndpointKind
+	// Anime and Manga report whether the field is returned for anime and for manga
+	Anime, Manga bool
+	// SubFields reports whether the field accepts a sub field selection, e.g. related_anime{rank}
+	SubFields bool
+
+	// nodes is the kind of media held by fields holding a list of anime or manga nodes. The sub fields of these
+	// are checked against what list endpoints support for that media.
+	nodes mediaKind
+}
+
+// Supports checks to see if the field can be requested from the kind of endpoint
+func (fi FieldInfo) Supports(kind EndpointKind) bool {
+	for _, k := range fi.Endpoints {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// mediaKind is the kind of media an endpoint returns
+type mediaKind string
+
+const (
+	mediaAnime mediaKind = "anime"
+	mediaManga mediaKind = "manga"
+	// mediaSame is used for nodes of the same media as the endpoint they are requested from
+	mediaSame mediaKind = "same"
+)
+
+// Groups of endpoints that fields are supported by
+var (
+	detailEndpoints = []EndpointKind{EndpointDetails}
+	allEndpoints    = []EndpointKind{EndpointDetails, EndpointList, EndpointRanking, EndpointSeason, EndpointSuggestions, EndpointUserList}
+)
+
+// fieldRegistry holds the FieldInfo for every Field
+var fieldRegistry = map[Field]FieldInfo{
+	FieldID:                     {Endpoints: allEndpoints, Anime: true, Manga: true},
+	FieldTitle:                  {Endpoints: allEndpoints, Anime: true, Manga: true},
+	FieldMainPicture:            {Endpoints: allEndpoints, Anime: true, Manga: true, SubFields: true},
+	FieldAlternativeTitles:      {Endpoints: allEndpoints, Anime: true, Manga: true, SubFields: true},
+	FieldStartDate:              {Endpoints: allEndpoints, Anime: true, Manga: true},
+	FieldEndDate:                {Endpoints: allEndpoints, Anime: true, Manga: true},
+	FieldSynopsis:               {Endpoints: allEndpoints, Anime: true, Manga: true},
+	FieldMean:                   {Endpoints: allEndpoints, Anime: true, Manga: true},
+	FieldRank:                   {Endpoints: allEndpoints, Anime: true, Manga: true},
+	FieldPopularity:             {Endpoints: allEndpoints, Anime: true, Manga: true},
+	FieldNumListUsers:           {Endpoints: allEndpoints, Anime: true, Manga: true},
+	FieldNumScoringUsers:        {Endpoints: allEndpoints, Anime: true, Manga: true},
+	FieldNsfw:                   {Endpoints: allEndpoints, Anime: true, Manga: true},
+	FieldGenres:                 {Endpoints: allEndpoints, Anime: true, Manga: true, SubFields: true},
+	FieldCreatedAt:              {Endpoints: allEndpoints, Anime: true, Manga: true},
+	FieldUpdatedAt:              {Endpoints: allEndpoints, Anime: true, Manga: true},
+	FieldMediaType:              {Endpoints: allEndpoints, Anime: true, Manga: true},
+	FieldStatus:                 {Endpoints: allEndpoints, Anime: true, Manga: true},
+	FieldNumEpisodes:            {Endpoints: allEndpoints, Anime: true},
+	FieldStartSeason:            {Endpoints: allEndpoints, Anime: true, SubFields: true},
+	FieldBroadcast:              {Endpoints: allEndpoints, Anime: true, SubFields: true},
+	FieldSource:                 {Endpoints: allEndpoints, Anime: true},
+	FieldAverageEpisodeDuration: {Endpoints: allEndpoints, Anime: true},
+	FieldRating:                 {Endpoints: detailEndpoints, Anime: true},
+	FieldPictures:               {Endpoints: detailEndpoints, Anime: true, Manga: true, SubFields: true},
+	FieldBackground:             {Endpoints: detailEndpoints, Anime: true, Manga: true},
+	FieldRelatedAnime:           {Endpoints: detailEndpoints, Anime: true, Manga: true, SubFields: true, nodes: mediaAnime},
+	FieldRelatedManga:           {Endpoints: detailEndpoints, Anime: true, Manga: true, SubFields: true, nodes: mediaManga},
+	FieldRecommendations:        {Endpoints: detailEndpoints, Anime: true, Manga: true, SubFields: true, nodes: mediaSame},
+	FieldStudios:                {Endpoints: allEndpoints, Anime: true, SubFields: true},
+	FieldStatistics:             {Endpoints: detailEndpoints, Anime: true, SubFields: true},
+	FieldListStatus:             {Endpoints: []EndpointKind{EndpointUserList}, Anime: true, SubFields: true},
+	FieldMyListStatus:           {Endpoints: allEndpoints, Anime: true, Manga: true, SubFields: true},
+	FieldNumVolumes:             {Endpoints: allEndpoints, Manga: true},
+	FieldNumChapters:            {Endpoints: allEndpoints, Manga: true},
+	FieldAuthors:                {Endpoints: allEndpoints, Manga: true, SubFields: true},
+	FieldSerialization:          {Endpoints: detailEndpoints, Manga: true, SubFields: true},
+}
+
+// LookupField returns the FieldInfo for a field, ignoring any sub fields attached to it. The second value is
+// false for fields that malgomate doesn't know about.
+func LookupField(f Field) (FieldInfo, bool) {
+	fi, ok := fieldRegistry[f.Name()]
+	return fi, ok
+}
+
+// validate checks that every field can be requested from the kind of endpoint, returning an error wrapping
+// ErrInvalidField for the first one that can't. Sub fields are checked all the way down. Fields that aren't in
+// the registry are passed through to MAL as is, so that new fields can be requested before malgomate knows
+// about them.
+func (fs Fields) validate(kind EndpointKind, media mediaKind) error {
+	for _, f := range fs {
+		name, sub, err := f.parse()
+		if err != nil {
+			return err
+		}
+		fi, ok := fieldRegistry[name]
+		if !ok {
+			continue
+		}
+
+		switch {
+		case media == mediaAnime && !fi.Anime, media == mediaManga && !fi.Manga:
+			return fmt.Errorf("%w: %s is not available on %s", ErrInvalidField, name, media)
+		case !fi.Supports(kind):
+			return fmt.Errorf("%w: %s can't be requested from %s %s endpoints", ErrInvalidField, name, media, kind)
+		case len(sub) > 0 && !fi.SubFields:
+			return fmt.Errorf("%w: %s doesn't accept sub fields", ErrInvalidField, name)
+		}
+
+		if len(sub) > 0 && fi.nodes != "" {
+			nodes := fi.nodes
+			if nodes == mediaSame {
+				nodes = media
+			}
+			if err := sub.validate(EndpointList, nodes); err != nil {
+				return fmt.Errorf("%w (in %s)", err, name)
+			}
+		}
+	}
+	return nil
+}
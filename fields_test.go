@@ -0,0 +1,127 @@
+package malgomate
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestFieldName(t *testing.T) {
+	testCases := []struct {
+		in       Field
+		expected Field
+	}{
+		{FieldTitle, FieldTitle},
+		{FieldRelatedAnime.SubFields(&Fields{FieldRank}), FieldRelatedAnime},
+		{Field("alternative_titles{en}"), FieldAlternativeTitles},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("Test case %d", i), func(t *testing.T) {
+			if got := tc.in.Name(); got != tc.expected {
+				t.Errorf("Expected %s, got %s", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestFieldAliases(t *testing.T) {
+	var qf QueryFields = Fields{FieldRating}
+	var df DetailFields = qf
+	if DetailRating != FieldRating || df.ToString() != "rating" {
+		t.Errorf("Expected detail fields to be interchangeable with fields")
+	}
+}
+
+func TestFieldsValidate(t *testing.T) {
+	testCases := []struct {
+		fields Fields
+		kind   EndpointKind
+		media  mediaKind
+		err    bool
+	}{
+		{BasicFieldQuery, EndpointList, mediaAnime, false},
+		{BasicDetailQuery, EndpointDetails, mediaManga, false},
+		{Fields{FieldRating}, EndpointDetails, mediaAnime, false},
+		{Fields{FieldRating}, EndpointList, mediaAnime, true},
+		{Fields{FieldRelatedAnime}, EndpointSeason, mediaAnime, true},
+		{Fields{FieldNumEpisodes}, EndpointRanking, mediaManga, true},
+		{Fields{FieldNumVolumes}, EndpointRanking, mediaManga, false},
+		{Fields{FieldNumVolumes}, EndpointDetails, mediaAnime, true},
+		{Fields{FieldListStatus}, EndpointUserList, mediaAnime, false},
+		{Fields{FieldListStatus}, EndpointList, mediaAnime, true},
+		{Fields{FieldRelatedAnime.SubFields(&Fields{FieldRank})}, EndpointDetails, mediaAnime, false},
+		{Fields{FieldTitle.SubFields(&Fields{FieldRank})}, EndpointDetails, mediaAnime, true},
+		{Fields{"some_new_field"}, EndpointList, mediaAnime, false},
+		{Fields{"alternative_titles{en,ja}", "my_list_status{status,score}"}, EndpointSeason, mediaAnime, false},
+		{Fields{"my_list_status{status,num_volumes_read}"}, EndpointDetails, mediaManga, false},
+		{Fields{"related_anime{rank,alternative_titles{en}}"}, EndpointDetails, mediaAnime, false},
+		{Fields{"related_anime{rating}"}, EndpointDetails, mediaAnime, true},
+		{Fields{"related_anime{num_volumes}"}, EndpointDetails, mediaAnime, true},
+		{Fields{"related_manga{num_volumes,authors{first_name}}"}, EndpointDetails, mediaAnime, false},
+		{Fields{"recommendations{num_volumes}"}, EndpointDetails, mediaManga, false},
+		{Fields{"recommendations{num_episodes}"}, EndpointDetails, mediaManga, true},
+		{Fields{"related_anime{alternative_titles{en},title{en}}"}, EndpointDetails, mediaAnime, true},
+		{Fields{"alternative_titles{en"}, EndpointList, mediaAnime, true},
+		{Fields{"alternative_titles{en}}"}, EndpointList, mediaAnime, true},
+		{Fields{"alternative_titles{en}{ja}"}, EndpointList, mediaAnime, true},
+		{Fields{"alternative_titles{en,}"}, EndpointList, mediaAnime, true},
+		{Fields{"{en}"}, EndpointList, mediaAnime, true},
+		{Fields{"title,rank"}, EndpointList, mediaAnime, true},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("Test case %d", i), func(t *testing.T) {
+			err := tc.fields.validate(tc.kind, tc.media)
+			if (err != nil) != tc.err {
+				t.Fatalf("Expected error %t, got %v", tc.err, err)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidField) {
+				t.Errorf("Expected ErrInvalidField, got %v", err)
+			}
+		})
+	}
+}
+
+func TestInvalidFieldNotSent(t *testing.T) {
+	var calls int
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+	})
+
+	_, err := c.GetAnime(&AnimeQuery{Query: "bebop", Fields: Fields{FieldTitle, FieldRating}})
+	if !errors.Is(err, ErrInvalidField) {
+		t.Errorf("Expected ErrInvalidField, got %v", err)
+	}
+	_, err = c.GetMangaDetails(&MangaDetailsQuery{Id: 1, Fields: Fields{FieldBroadcast}})
+	if !errors.Is(err, ErrInvalidField) {
+		t.Errorf("Expected ErrInvalidField, got %v", err)
+	}
+	if calls != 0 {
+		t.Errorf("Expected no requests to be made, got %d", calls)
+	}
+}
+
+func TestListSubFieldsSent(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		expected := "id,alternative_titles{en},my_list_status{status,num_episodes_watched}"
+		if got := r.URL.Query().Get("fields"); got != expected {
+			t.Errorf("Expected fields %q, got %q", expected, got)
+		}
+		fmt.Fprint(w, `{"data": [{"node": {"id": 1, "alternative_titles": {"en": "Cowboy Bebop"}, "my_list_status": {"status": "watching", "num_episodes_watched": 3}}}], "paging": {}}`)
+	})
+
+	res, err := c.GetSeason(&SeasonalQuery{Year: 1998, Season: SeasonSpring, Fields: QueryFields{
+		FieldID,
+		FieldAlternativeTitles.SubFields(&QueryFields{"en"}),
+		FieldMyListStatus.SubFields(&QueryFields{"status", "num_episodes_watched"}),
+	}})
+	if err != nil {
+		t.Fatalf("Unexpected error: %q", err)
+	}
+	a := res.Data[0].Node
+	if a.AlternativeTitles.En != "Cowboy Bebop" || a.MyListStatus == nil || a.MyListStatus.NumEpisodesWatched != 3 {
+		t.Errorf("Unexpected anime %+v", a)
+	}
+}
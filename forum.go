@@ -0,0 +1,239 @@
+package malgomate
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// ForumBoards is the full list of MAL forum boards, grouped by category
+type ForumBoards struct {
+	Categories []ForumCategory `json:"categories"`
+}
+
+// JSON is a helper function that converts a ForumBoards object to a JSON string
+func (fb *ForumBoards) JSON() (string, error) {
+	if s, err := json.MarshalIndent(fb, "", "  "); err == nil {
+		return string(s), err
+	} else {
+		return "", err
+	}
+}
+
+// ForumCategory is a group of forum boards
+type ForumCategory struct {
+	Title  string       `json:"title"`
+	Boards []ForumBoard `json:"boards"`
+}
+
+// ForumBoard is a single forum board, which may be broken up further into subboards
+type ForumBoard struct {
+	ID          int             `json:"id"`
+	Title       string          `json:"title"`
+	Description string          `json:"description,omitempty"`
+	Subboards   []ForumSubboard `json:"subboards,omitempty"`
+}
+
+// ForumSubboard is a subsection of a forum board
+type ForumSubboard struct {
+	ID    int    `json:"id"`
+	Title string `json:"title"`
+}
+
+// ForumTopic is a summary of a forum topic, as returned when searching topics
+type ForumTopic struct {
+	ID                int        `json:"id"`
+	Title             string     `json:"title"`
+	CreatedAt         *Timestamp `json:"created_at,omitempty"`
+	CreatedBy         *ForumUser `json:"created_by,omitempty"`
+	NumberOfPosts     int        `json:"number_of_posts"`
+	LastPostCreatedAt *Timestamp `json:"last_post_created_at,omitempty"`
+	LastPostCreatedBy *ForumUser `json:"last_post_created_by,omitempty"`
+	IsLocked          bool       `json:"is_locked"`
+}
+
+// ForumUser is the MAL user behind a forum topic or post
+type ForumUser struct {
+	ID          int    `json:"id"`
+	Name        string `json:"name"`
+	ForumAvatar string `json:"forum_avator,omitempty"`
+}
+
+// ForumTopicPage is a paginated response page for the posts of a single forum topic
+type ForumTopicPage struct {
+	Topic  ForumTopicDetail `json:"data"`
+	Paging Paging           `json:"paging"`
+}
+
+// JSON is a helper function converts a ForumTopicPage to a JSON string
+func (ftp *ForumTopicPage) JSON() (string, error) {
+	if s, err := json.MarshalIndent(ftp, "", "  "); err == nil {
+		return string(s), err
+	} else {
+		return "", err
+	}
+}
+
+// ForumTopicDetail holds the posts of a forum topic, along with its poll if it has one
+type ForumTopicDetail struct {
+	Title string      `json:"title"`
+	Posts []ForumPost `json:"posts"`
+	Poll  *ForumPoll  `json:"poll,omitempty"`
+}
+
+// ForumPost is a single post in a forum topic
+type ForumPost struct {
+	ID        int        `json:"id"`
+	Number    int        `json:"number"`
+	CreatedAt *Timestamp `json:"created_at,omitempty"`
+	CreatedBy *ForumUser `json:"created_by,omitempty"`
+	Body      string     `json:"body"`
+	Signature string     `json:"signature,omitempty"`
+}
+
+// ForumPoll is a poll attached to a forum topic
+type ForumPoll struct {
+	ID       int               `json:"id"`
+	Question string            `json:"question"`
+	Closed   bool              `json:"close"`
+	Options  []ForumPollOption `json:"options"`
+}
+
+// ForumPollOption is one of the choices in a forum poll
+type ForumPollOption struct {
+	ID    int    `json:"id"`
+	Text  string `json:"text"`
+	Votes int    `json:"votes"`
+}
+
+// ForumTopicsQuery is used to search forum topics. At least one of Query, BoardID, SubboardID, TopicUserName or
+// UserName must be set. TopicUserName filters on the user that created the topic, while UserName filters on
+// any user that posted in it.
+type ForumTopicsQuery struct {
+	Query         string
+	BoardID       int
+	SubboardID    int
+	TopicUserName string
+	UserName      string
+	Limit         int
+	Offset        int
+}
+
+// ForumTopicQuery is used to query the posts of a specific forum topic. The Id must be set.
+type ForumTopicQuery struct {
+	Id     int
+	Limit  int
+	Offset int
+}
+
+// GetForumBoards retrieves the full list of MAL forum boards.
+func (c *Client) GetForumBoards() (*ForumBoards, error) {
+	return c.GetForumBoardsContext(context.Background())
+}
+
+// GetForumBoardsContext is the same as GetForumBoards, but the request is bound to the provided context.
+// Cancelling the context aborts the request.
+func (c *Client) GetForumBoardsContext(ctx context.Context) (*ForumBoards, error) {
+	queryString := fmt.Sprintf("%s/forum/boards", c.BaseURL)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, queryString, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res := ForumBoards{}
+	if err := c.sendRequest(req, &res); err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+// GetForumTopics searches forum topics. These queries return a paged list of topics, most recent first. If not
+// included, the following default values will be used:
+//   - Limit - 100 (max 100)
+//   - Offset - 0
+func (c *Client) GetForumTopics(q *ForumTopicsQuery) (*ForumTopicsPage, error) {
+	return c.GetForumTopicsContext(context.Background(), q)
+}
+
+// GetForumTopicsContext is the same as GetForumTopics, but the request is bound to the provided context.
+// Cancelling the context aborts the request.
+func (c *Client) GetForumTopicsContext(ctx context.Context, q *ForumTopicsQuery) (*ForumTopicsPage, error) {
+	// Check for required values
+	if q.Query == "" && q.BoardID == 0 && q.SubboardID == 0 && q.TopicUserName == "" && q.UserName == "" {
+		return nil, errors.New("missing required parameter: one of Query, BoardID, SubboardID, TopicUserName or UserName must be set")
+	}
+	// Handle defaults
+	if q.Limit == 0 {
+		q.Limit = 100
+	} else if q.Limit > SmallQueryLimit {
+		q.Limit = SmallQueryLimit
+	}
+
+	queryString := fmt.Sprintf("%s/forum/topics?sort=recent&limit=%d&offset=%d", c.BaseURL, q.Limit, q.Offset)
+	if q.Query != "" {
+		queryString += fmt.Sprintf("&q=%s", url.QueryEscape(q.Query))
+	}
+	if q.BoardID != 0 {
+		queryString += fmt.Sprintf("&board_id=%d", q.BoardID)
+	}
+	if q.SubboardID != 0 {
+		queryString += fmt.Sprintf("&subboard_id=%d", q.SubboardID)
+	}
+	if q.TopicUserName != "" {
+		queryString += fmt.Sprintf("&topic_user_name=%s", url.QueryEscape(q.TopicUserName))
+	}
+	if q.UserName != "" {
+		queryString += fmt.Sprintf("&user_name=%s", url.QueryEscape(q.UserName))
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, queryString, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res := ForumTopicsPage{}
+	if err := c.sendRequest(req, &res); err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+// GetForumTopic retrieves the posts of a forum topic, along with its poll if it has one. These queries return a
+// paged list of posts. If not included, the following default values will be used:
+//   - Limit - 100 (max 100)
+//   - Offset - 0
+func (c *Client) GetForumTopic(q *ForumTopicQuery) (*ForumTopicPage, error) {
+	return c.GetForumTopicContext(context.Background(), q)
+}
+
+// GetForumTopicContext is the same as GetForumTopic, but the request is bound to the provided context.
+// Cancelling the context aborts the request.
+func (c *Client) GetForumTopicContext(ctx context.Context, q *ForumTopicQuery) (*ForumTopicPage, error) {
+	// Check for required values
+	if q.Id == 0 {
+		return nil, errors.New("missing required parameter: Id must be set")
+	}
+	// Handle defaults
+	if q.Limit == 0 {
+		q.Limit = 100
+	} else if q.Limit > SmallQueryLimit {
+		q.Limit = SmallQueryLimit
+	}
+
+	queryString := fmt.Sprintf("%s/forum/topic/%d?limit=%d&offset=%d", c.BaseURL, q.Id, q.Limit, q.Offset)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, queryString, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res := ForumTopicPage{}
+	if err := c.sendRequest(req, &res); err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
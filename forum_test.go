@@ -0,0 +1,97 @@
+package malgomate
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestGetForumBoards(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/forum/boards" {
+			t.Errorf("Unexpected path %q", r.URL.Path)
+		}
+		fmt.Fprint(w, `{"categories": [{"title": "Anime & Manga", "boards": [{"id": 1, "title": "Anime Discussion", "description": "General anime discussion", "subboards": [{"id": 2, "title": "Anime Series"}]}]}]}`)
+	})
+
+	res, err := c.GetForumBoards()
+	if err != nil {
+		t.Fatalf("Unexpected error: %q", err)
+	}
+	if len(res.Categories) != 1 || len(res.Categories[0].Boards) != 1 || res.Categories[0].Boards[0].Subboards[0].ID != 2 {
+		t.Errorf("Unexpected boards %+v", res)
+	}
+}
+
+func TestGetForumTopics(t *testing.T) {
+	testCases := []struct {
+		in       ForumTopicsQuery
+		expected string
+	}{
+		{ForumTopicsQuery{Query: "one piece"}, "limit=100&offset=0&q=one+piece&sort=recent"},
+		{ForumTopicsQuery{BoardID: 1, SubboardID: 2, Limit: 500, Offset: 10}, "board_id=1&limit=100&offset=10&sort=recent&subboard_id=2"},
+		{ForumTopicsQuery{TopicUserName: "a", UserName: "b", Limit: 5}, "limit=5&offset=0&sort=recent&topic_user_name=a&user_name=b"},
+		{ForumTopicsQuery{}, ""},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("Test case %d", i), func(t *testing.T) {
+			var base string
+			var calls int
+			c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				calls++
+				if got := r.URL.Query().Encode(); calls == 1 && (r.URL.Path != "/forum/topics" || got != tc.expected) {
+					t.Errorf("Expected query %q, got %q", tc.expected, got)
+				}
+				fmt.Fprintf(w, `{"data": [{"id": 7, "title": "Topic", "created_at": "2022-01-02T03:04:05+00:00", "created_by": {"id": 1, "name": "someone"}, "number_of_posts": 3, "is_locked": true}], "paging": {"next": "%s/forum/topics?offset=1"}}`, base)
+			})
+			base = c.BaseURL
+
+			res, err := c.GetForumTopics(&tc.in)
+			if tc.expected == "" {
+				if err == nil {
+					t.Errorf("Expected an error for a query without filters")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %q", err)
+			}
+			if len(res.Data) != 1 || res.Data[0].CreatedBy.Name != "someone" || !res.Data[0].IsLocked || res.Data[0].CreatedAt.Year() != 2022 {
+				t.Errorf("Unexpected topics %+v", res.Data)
+			}
+
+			next := ForumTopicsPage{}
+			if err := c.GetNextPage(&res.Paging, &next); err != nil || len(next.Data) != 1 {
+				t.Errorf("Expected the next page to be fetched, got %v", err)
+			}
+		})
+	}
+}
+
+func TestGetForumTopic(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/forum/topic/7" || r.URL.Query().Get("limit") != "10" {
+			t.Errorf("Unexpected request %q", r.URL)
+		}
+		fmt.Fprint(w, `{
+			"data": {
+				"title": "Topic",
+				"posts": [{"id": 1, "number": 1, "created_at": "2022-01-02T03:04:05+00:00", "created_by": {"id": 1, "name": "someone", "forum_avator": "https://example.com/a.png"}, "body": "hello"}],
+				"poll": {"id": 3, "question": "Best girl?", "close": true, "options": [{"id": 1, "text": "All of them", "votes": 42}]}
+			},
+			"paging": {}
+		}`)
+	})
+
+	res, err := c.GetForumTopic(&ForumTopicQuery{Id: 7, Limit: 10})
+	if err != nil {
+		t.Fatalf("Unexpected error: %q", err)
+	}
+	if len(res.Topic.Posts) != 1 || res.Topic.Posts[0].CreatedBy.ForumAvatar == "" || res.Topic.Posts[0].Body != "hello" || res.Topic.Posts[0].CreatedAt == nil {
+		t.Errorf("Unexpected posts %+v", res.Topic.Posts)
+	}
+	if res.Topic.Poll == nil || !res.Topic.Poll.Closed || res.Topic.Poll.Options[0].Votes != 42 {
+		t.Errorf("Unexpected poll %+v", res.Topic.Poll)
+	}
+}
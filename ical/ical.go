@@ -0,0 +1,207 @@
+// Package ical exports the weekly broadcast schedule of a MAL (MyAnimeList) season as an RFC 5545 iCalendar
+// feed, which calendar apps can subscribe to. Each anime becomes a recurring weekly event in Japan time, ending
+// after its expected last episode.
+package ical
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	mal "github.com/fuzzylimes/malgomate"
+)
+
+// TZID is the time zone that events are scheduled in
+const TZID = "Asia/Tokyo"
+
+// timeLayout is the layout used for local and UTC date-times
+const timeLayout = "20060102T150405"
+
+// maxLineLength is the longest a content line can be, in octets, before it has to be folded
+const maxLineLength = 75
+
+// defaultDuration is used for anime without an average episode duration
+const defaultDuration = 30 * time.Minute
+
+// Fields are the fields that need to be requested from GetSeason for anime to be added to a calendar
+var Fields = mal.QueryFields{
+	mal.FieldID,
+	mal.FieldTitle,
+	mal.FieldStartDate,
+	mal.FieldBroadcast,
+	mal.FieldNumEpisodes,
+	mal.FieldAverageEpisodeDuration,
+}
+
+// Event is the weekly broadcast of a single anime
+type Event struct {
+	UID      string
+	Summary  string
+	URL      string
+	Start    time.Time
+	Duration time.Duration
+	// Count is the number of broadcasts, or 0 when the number of episodes isn't known yet
+	Count int
+}
+
+// Calendar is a set of broadcast events, written out with WriteTo
+type Calendar struct {
+	Name   string
+	Events []Event
+	// Stamp is when the calendar was created, defaulting to the current time
+	Stamp time.Time
+}
+
+// New is a constructor for an empty calendar with the given display name
+func New(name string) *Calendar {
+	return &Calendar{Name: name, Stamp: time.Now()}
+}
+
+// FromSeason is a constructor for a calendar of every anime in the season pages. See AddPage for which anime
+// are included.
+func FromSeason(name string, pages ...*mal.ListPage) *Calendar {
+	c := New(name)
+	for _, p := range pages {
+		c.AddPage(p)
+	}
+	return c
+}
+
+// AddPage adds an event for each anime on a page returned by GetSeason. The page must be requested with Fields.
+// Anime without a regular weekly broadcast or a known start date are skipped.
+func (c *Calendar) AddPage(p *mal.ListPage) {
+	for i := range p.Data {
+		c.Add(&p.Data[i].Node)
+	}
+}
+
+// Add adds an event for the anime, returning false (and adding nothing) when it doesn't have a regular weekly
+// broadcast or a known start date.
+func (c *Calendar) Add(a *mal.Anime) bool {
+	first, err := a.FirstAiring(mal.JST)
+	if err != nil {
+		return false
+	}
+
+	duration := time.Duration(a.AverageEpisodeDuration) * time.Second
+	if duration <= 0 {
+		duration = defaultDuration
+	}
+
+	c.Events = append(c.Events, Event{
+		UID:      fmt.Sprintf("anime-%d@malgomate", a.ID),
+		Summary:  a.Title,
+		URL:      fmt.Sprintf("https://myanimelist.net/anime/%d", a.ID),
+		Start:    first,
+		Duration: duration,
+		Count:    a.NumEpisodes,
+	})
+	return true
+}
+
+// WriteTo writes the calendar out in iCalendar format, implementing io.WriterTo
+func (c *Calendar) WriteTo(w io.Writer) (int64, error) {
+	stamp := c.Stamp
+	if stamp.IsZero() {
+		stamp = time.Now()
+	}
+
+	cw := &contentWriter{w: bufio.NewWriter(w)}
+	cw.line("BEGIN:VCALENDAR")
+	cw.line("VERSION:2.0")
+	cw.line("PRODID:-//fuzzylimes//malgomate//EN")
+	cw.line("CALSCALE:GREGORIAN")
+	cw.line("METHOD:PUBLISH")
+	if c.Name != "" {
+		cw.line("X-WR-CALNAME:" + escape(c.Name))
+	}
+	cw.line("X-WR-TIMEZONE:" + TZID)
+
+	cw.line("BEGIN:VTIMEZONE")
+	cw.line("TZID:" + TZID)
+	cw.line("BEGIN:STANDARD")
+	cw.line("DTSTART:19700101T000000")
+	cw.line("TZOFFSETFROM:+0900")
+	cw.line("TZOFFSETTO:+0900")
+	cw.line("TZNAME:JST")
+	cw.line("END:STANDARD")
+	cw.line("END:VTIMEZONE")
+
+	for _, e := range c.Events {
+		cw.line("BEGIN:VEVENT")
+		cw.line("UID:" + escape(e.UID))
+		cw.line("DTSTAMP:" + stamp.UTC().Format(timeLayout) + "Z")
+		cw.line("DTSTART;TZID=" + TZID + ":" + e.Start.In(mal.JST).Format(timeLayout))
+		cw.line(fmt.Sprintf("DURATION:PT%dS", int64(e.Duration/time.Second)))
+		if e.Count > 0 {
+			cw.line(fmt.Sprintf("RRULE:FREQ=WEEKLY;COUNT=%d", e.Count))
+		} else {
+			cw.line("RRULE:FREQ=WEEKLY")
+		}
+		cw.line("SUMMARY:" + escape(e.Summary))
+		if e.URL != "" {
+			cw.line("URL:" + e.URL)
+		}
+		cw.line("END:VEVENT")
+	}
+	cw.line("END:VCALENDAR")
+
+	if cw.err == nil {
+		cw.err = cw.w.Flush()
+	}
+	return cw.n, cw.err
+}
+
+// String returns the calendar in iCalendar format
+func (c *Calendar) String() string {
+	var sb strings.Builder
+	c.WriteTo(&sb)
+	return sb.String()
+}
+
+// contentWriter writes folded content lines, holding on to the first error
+type contentWriter struct {
+	w   *bufio.Writer
+	n   int64
+	err error
+}
+
+// line writes a content line terminated by CRLF, folding it onto continuation lines so that no line is longer
+// than 75 octets. Lines are only split between characters, never in the middle of a multi-byte one.
+func (cw *contentWriter) line(s string) {
+	if cw.err != nil {
+		return
+	}
+
+	var sb strings.Builder
+	limit := maxLineLength
+	for len(s) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(s[cut]) {
+			cut--
+		}
+		sb.WriteString(s[:cut])
+		sb.WriteString("\r\n ")
+		s = s[cut:]
+		// Continuation lines start with a space, which counts towards their length
+		limit = maxLineLength - 1
+	}
+	sb.WriteString(s)
+	sb.WriteString("\r\n")
+
+	n, err := cw.w.WriteString(sb.String())
+	cw.n += int64(n)
+	cw.err = err
+}
+
+// escape escapes a TEXT value
+var escape = strings.NewReplacer(
+	`\`, `\\`,
+	`;`, `\;`,
+	`,`, `\,`,
+	"\r\n", `\n`,
+	"\n", `\n`,
+).Replace
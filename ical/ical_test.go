@@ -0,0 +1,85 @@
+package ical_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	mal "github.com/fuzzylimes/malgomate"
+	"github.com/fuzzylimes/malgomate/ical"
+)
+
+func date(str string) *mal.PartialDate {
+	d, _ := mal.ParsePartialDate(str)
+	return &d
+}
+
+func TestFromSeason(t *testing.T) {
+	page := &mal.ListPage{Data: []mal.Listing{
+		{Node: mal.Anime{
+			ID:                     48736,
+			Title:                  "Sono Bisque Doll wa Koi wo Suru",
+			StartDate:              date("2022-01-09"),
+			Broadcast:              &mal.Broadcast{DayOfTheWeek: "sunday", StartTime: "24:00"},
+			NumEpisodes:            12,
+			AverageEpisodeDuration: 1420,
+		}},
+		{Node: mal.Anime{
+			ID:        1,
+			Title:     "Movie, the; part 1\\2",
+			StartDate: date("2022-01-05"),
+			Broadcast: &mal.Broadcast{DayOfTheWeek: "wednesday", StartTime: "18:00"},
+		}},
+		// Skipped, doesn't air weekly
+		{Node: mal.Anime{ID: 2, Title: "OVA", StartDate: date("2022-01-05"), Broadcast: &mal.Broadcast{DayOfTheWeek: "other"}}},
+		// Skipped, start date unknown
+		{Node: mal.Anime{ID: 3, Title: "TBA", StartDate: date("2022"), Broadcast: &mal.Broadcast{DayOfTheWeek: "monday", StartTime: "12:00"}}},
+	}}
+
+	c := ical.FromSeason("Winter 2022", page)
+	c.Stamp = time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	if len(c.Events) != 2 {
+		t.Fatalf("Expected 2 events, got %d", len(c.Events))
+	}
+
+	out := c.String()
+	if !strings.HasSuffix(out, "END:VCALENDAR\r\n") || strings.Contains(strings.ReplaceAll(out, "\r\n", ""), "\n") {
+		t.Errorf("Expected all lines to end in CRLF")
+	}
+
+	for _, expected := range []string{
+		"BEGIN:VTIMEZONE\r\nTZID:Asia/Tokyo\r\n",
+		"TZOFFSETTO:+0900\r\n",
+		"UID:anime-48736@malgomate\r\nDTSTAMP:20220101T000000Z\r\n",
+		"DTSTART;TZID=Asia/Tokyo:20220110T000000\r\nDURATION:PT1420S\r\nRRULE:FREQ=WEEKLY;COUNT=12\r\n",
+		"DTSTART;TZID=Asia/Tokyo:20220105T180000\r\nDURATION:PT1800S\r\nRRULE:FREQ=WEEKLY\r\n",
+		`SUMMARY:Movie\, the\; part 1\\2` + "\r\n",
+		"X-WR-CALNAME:Winter 2022\r\n",
+	} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("Expected calendar to contain %q, got:\n%s", expected, out)
+		}
+	}
+}
+
+func TestFolding(t *testing.T) {
+	title := strings.Repeat("進撃の巨人 ", 30)
+	c := ical.New("")
+	c.Add(&mal.Anime{ID: 1, Title: title, StartDate: date("2022-01-09"), Broadcast: &mal.Broadcast{DayOfTheWeek: "sunday", StartTime: "00:10"}})
+
+	out := c.String()
+	for _, line := range strings.Split(strings.TrimSuffix(out, "\r\n"), "\r\n") {
+		if len(line) > 75 {
+			t.Errorf("Expected line of at most 75 octets, got %d: %q", len(line), line)
+		}
+		if !utf8.ValidString(line) {
+			t.Errorf("Expected folding to keep characters whole, got %q", line)
+		}
+	}
+
+	unfolded := strings.ReplaceAll(out, "\r\n ", "")
+	if !strings.Contains(unfolded, "SUMMARY:"+title+"\r\n") {
+		t.Errorf("Expected unfolded summary to match the title")
+	}
+}
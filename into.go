@@ -0,0 +1,261 @@
+package malgomate
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+)
+
+// ListEntry is an entry on a page returned by GetAnimeInto, GetSeasonInto or GetSuggestionsInto, holding a caller
+// supplied type in place of Anime. The same as Listing otherwise.
+type ListEntry[T any] struct {
+	Node T `json:"node"`
+}
+
+// RankingEntry is an entry on a page returned by GetRankingInto, holding a caller supplied type in place of
+// Anime. The same as Ranking otherwise.
+type RankingEntry[T any] struct {
+	Node T    `json:"node"`
+	Rank Rank `json:"ranking"`
+}
+
+// UserListEntry is an entry on a page returned by GetUserAnimeListInto, holding a caller supplied type in place
+// of Anime. The same as UserAnimeListing otherwise.
+type UserListEntry[T any] struct {
+	Node       T           `json:"node"`
+	ListStatus *ListStatus `json:"list_status,omitempty"`
+}
+
+// FieldsOf builds the fields needed to fill in every json tagged field of v, which must be a struct or a pointer
+// to one. Fields holding lists of nodes (such as related_anime, recommendations or authors) have sub fields built
+// from the struct tagged "node", and fields holding objects (such as alternative_titles) from the object itself.
+// Struct fields without a json tag are skipped, while embedded structs have their fields included as if they
+// were declared on v itself.
+func FieldsOf(v interface{}) (Fields, error) {
+	t := reflect.TypeOf(v)
+	if t == nil {
+		return nil, errors.New("missing required parameter: v must be a struct")
+	}
+	t = elem(t)
+	if t.Kind() != reflect.Struct {
+		return nil, errors.New("missing required parameter: v must be a struct")
+	}
+	return fieldsOf(t, "", map[reflect.Type]bool{}), nil
+}
+
+// fieldsOf builds the fields for a struct type. Types that are already being built further up are left alone,
+// so that recursive types like Anime don't go on forever. When building the sub fields of a node, fields that
+// MAL can't return for nodes are left out, so that full types like Manga can be used as nodes.
+func fieldsOf(t reflect.Type, node mediaKind, building map[reflect.Type]bool) Fields {
+	building[t] = true
+	defer delete(building, t)
+
+	var fields Fields
+	seen := map[Field]bool{}
+	add := func(f Field) {
+		if !seen[f.Name()] {
+			seen[f.Name()] = true
+			fields = append(fields, f)
+		}
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		name, ok := jsonName(sf)
+		if !ok {
+			if sf.Anonymous && elem(sf.Type).Kind() == reflect.Struct && strings.Split(sf.Tag.Get("json"), ",")[0] == "" {
+				for _, f := range fieldsOf(elem(sf.Type), node, building) {
+					add(f)
+				}
+			}
+			continue
+		}
+
+		f := Field(name)
+		fi, ok := fieldRegistry[f]
+		if ok && node != "" && (!fi.Supports(EndpointList) || node == mediaAnime && !fi.Anime || node == mediaManga && !fi.Manga) {
+			continue
+		}
+		if ok && fi.SubFields {
+			// Sub fields of lists of nodes, like related_anime or authors, select from the node
+			ft := elem(sf.Type)
+			if nt := nodeType(ft); nt != nil {
+				ft = nt
+			}
+			if ft != nil && ft.Kind() == reflect.Struct && !building[ft] {
+				subFields := fieldsOf(ft, fi.nodes, building)
+				f = f.SubFields(&subFields)
+			}
+		}
+		add(f)
+	}
+	return fields
+}
+
+// jsonName is the name a struct field is encoded with, if it has one
+func jsonName(sf reflect.StructField) (string, bool) {
+	if !sf.IsExported() {
+		return "", false
+	}
+	tag, ok := sf.Tag.Lookup("json")
+	if !ok {
+		return "", false
+	}
+	name := strings.Split(tag, ",")[0]
+	if name == "" || name == "-" {
+		return "", false
+	}
+	return name, true
+}
+
+// elem unwraps pointers, slices and arrays down to the type they hold
+func elem(t reflect.Type) reflect.Type {
+	for {
+		switch t.Kind() {
+		case reflect.Ptr, reflect.Slice, reflect.Array:
+			t = t.Elem()
+		default:
+			return t
+		}
+	}
+}
+
+// nodeType finds the type of the field tagged "node" on a struct, returning nil when there isn't one
+func nodeType(t reflect.Type) reflect.Type {
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+	for i := 0; i < t.NumField(); i++ {
+		if name, ok := jsonName(t.Field(i)); ok && name == "node" {
+			return elem(t.Field(i).Type)
+		}
+	}
+	return nil
+}
+
+// GetDetailsInto retrieves specifics for a given MAL anime Id, decoding them into T, which must be a struct. The
+// fields requested are built from T's json tags with FieldsOf, so only what T can hold is fetched.
+func GetDetailsInto[T any](ctx context.Context, c *Client, id int) (*T, error) {
+	q := DetailsQuery{Id: id}
+	if err := fieldsInto[T](&q.Fields); err != nil {
+		return nil, err
+	}
+	req, err := c.detailsRequest(ctx, &q)
+	if err != nil {
+		return nil, err
+	}
+
+	var res T
+	if err := c.sendRequest(req, &res); err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+// GetAnimeInto is the same as GetAnimeContext, but each anime is decoded into T. The query's fields are replaced
+// with those built from T's json tags by FieldsOf.
+func GetAnimeInto[T any](ctx context.Context, c *Client, aq *AnimeQuery) (*Page[ListEntry[T]], error) {
+	if err := fieldsInto[T](&aq.Fields); err != nil {
+		return nil, err
+	}
+	req, err := c.animeRequest(ctx, aq)
+	if err != nil {
+		return nil, err
+	}
+
+	res := Page[ListEntry[T]]{}
+	if err := c.sendRequest(req, &res); err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+// GetRankingInto is the same as GetRankingContext, but each anime is decoded into T. The query's fields are
+// replaced with those built from T's json tags by FieldsOf.
+func GetRankingInto[T any](ctx context.Context, c *Client, r *RankingQuery) (*Page[RankingEntry[T]], error) {
+	if err := fieldsInto[T](&r.Fields); err != nil {
+		return nil, err
+	}
+	req, err := c.rankingRequest(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+
+	res := Page[RankingEntry[T]]{}
+	if err := c.sendRequest(req, &res); err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+// GetSeasonInto is the same as GetSeasonContext, but each anime is decoded into T. The query's fields are replaced
+// with those built from T's json tags by FieldsOf.
+func GetSeasonInto[T any](ctx context.Context, c *Client, q *SeasonalQuery) (*Page[ListEntry[T]], error) {
+	if err := fieldsInto[T](&q.Fields); err != nil {
+		return nil, err
+	}
+	req, err := c.seasonRequest(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	res := Page[ListEntry[T]]{}
+	if err := c.sendRequest(req, &res); err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+// GetSuggestionsInto is the same as GetSuggestionsContext, but each anime is decoded into T. The query's fields
+// are replaced with those built from T's json tags by FieldsOf.
+func GetSuggestionsInto[T any](ctx context.Context, c *Client, q *SuggestionsQuery) (*Page[ListEntry[T]], error) {
+	if err := fieldsInto[T](&q.Fields); err != nil {
+		return nil, err
+	}
+	req, err := c.suggestionsRequest(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	res := Page[ListEntry[T]]{}
+	if err := c.sendRequest(req, &res); err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+// GetUserAnimeListInto is the same as GetUserAnimeListContext, but each anime is decoded into T. The query's
+// fields are replaced with those built from T's json tags by FieldsOf, and the list status is always requested.
+func GetUserAnimeListInto[T any](ctx context.Context, c *Client, q *UserAnimeListQuery) (*Page[UserListEntry[T]], error) {
+	if err := fieldsInto[T](&q.Fields); err != nil {
+		return nil, err
+	}
+	req, err := c.userAnimeListRequest(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	res := Page[UserListEntry[T]]{}
+	if err := c.sendRequest(req, &res); err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+// fieldsInto sets fields to those built from T
+func fieldsInto[T any](fields *Fields) error {
+	var v T
+	f, err := FieldsOf(&v)
+	if err != nil {
+		return err
+	}
+	*fields = f
+	return nil
+}
@@ -0,0 +1,201 @@
+package malgomate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/fuzzylimes/malgomate/auth"
+)
+
+type trimmedAnime struct {
+	ID                int    `json:"id"`
+	Title             string `json:"title"`
+	Mean              float64
+	Ignored           string `json:"-"`
+	AlternativeTitles struct {
+		En string `json:"en"`
+	} `json:"alternative_titles"`
+}
+
+type trimmedRelated struct {
+	Node         trimmedAnime `json:"node"`
+	RelationType string       `json:"relation_type"`
+}
+
+type trimmedDetails struct {
+	trimmedAnime
+	Rating       Rating `json:"rating,omitempty"`
+	RelatedAnime []*struct {
+		Node struct {
+			ID   int `json:"id"`
+			Rank int `json:"rank"`
+		} `json:"node"`
+	} `json:"related_anime"`
+	Recommendations []trimmedRelated `json:"recommendations"`
+	StartDate       *PartialDate     `json:"start_date"`
+}
+
+func TestFieldsOf(t *testing.T) {
+	testCases := []struct {
+		in       interface{}
+		expected string
+	}{
+		{trimmedAnime{}, "id,title,alternative_titles{en}"},
+		{&trimmedDetails{}, "id,title,alternative_titles{en},rating,related_anime{id,rank},recommendations{id,title,alternative_titles{en}},start_date"},
+		{&struct {
+			Broadcast *Broadcast `json:"broadcast"`
+			Unknown   struct {
+				A int `json:"a"`
+			} `json:"some_new_field"`
+		}{}, "broadcast{day_of_the_week,start_time},some_new_field"},
+		{&struct {
+			Authors []MangaAuthor `json:"authors"`
+		}{}, "authors{id,first_name,last_name}"},
+		// Anime's nodes are Anime themselves, so they aren't expanded, while manga nodes drop detail only fields
+		{Anime{}, "id,title,main_picture{medium,large},alternative_titles{synonyms,en,ja},start_date,end_date,synopsis," +
+			"mean,rank,popularity,num_list_users,num_scoring_users,nsfw,created_at,updated_at,media_type,status," +
+			"genres{id,name},num_episodes,start_season{year,season},broadcast{day_of_the_week,start_time},source," +
+			"average_episode_duration,rating,pictures{medium,large},background,related_anime," +
+			"related_manga{id,title,main_picture{medium,large},alternative_titles{synonyms,en,ja},start_date,end_date," +
+			"synopsis,mean,rank,popularity,num_list_users,num_scoring_users,nsfw,genres{id,name},created_at,updated_at," +
+			"media_type,status,num_volumes,num_chapters,authors{id,first_name,last_name}," +
+			"my_list_status{status,score,num_volumes_read,num_chapters_read,is_rereading,updated_at,start_date," +
+			"finish_date,priority,num_times_reread,reread_value,tags,comments}}," +
+			"recommendations,studios{id,name},statistics{status,num_list_users}," +
+			"my_list_status{status,score,num_episodes_watched,is_rewatching,updated_at,start_date,finish_date,priority," +
+			"num_times_rewatched,rewatch_value,tags,comments}"},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("Test case %d", i), func(t *testing.T) {
+			got, err := FieldsOf(tc.in)
+			if err != nil {
+				t.Fatalf("Unexpected error: %q", err)
+			}
+			if got.ToString() != tc.expected {
+				t.Errorf("Expected %s, got %s", tc.expected, got.ToString())
+			}
+		})
+	}
+
+	if fields, _ := FieldsOf(Anime{}); fields.validate(EndpointDetails, mediaAnime) != nil {
+		t.Errorf("Expected the fields of Anime to be valid detail fields")
+	}
+	if _, err := FieldsOf(5); err == nil {
+		t.Errorf("Expected an error for a non struct")
+	}
+}
+
+func TestGetDetailsInto(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		expected := "id,title,alternative_titles{en},rating,related_anime{id,rank},recommendations{id,title,alternative_titles{en}},start_date"
+		if r.URL.Path != "/anime/30" || r.URL.Query().Get("fields") != expected {
+			t.Errorf("Unexpected request %q", r.URL)
+		}
+		fmt.Fprint(w, `{"id": 30, "title": "Neon Genesis Evangelion", "alternative_titles": {"en": "Neon Genesis Evangelion"}, "rating": "pg_13", "start_date": "1995-10-04",
+			"related_anime": [{"node": {"id": 32, "rank": 270}}], "recommendations": [{"node": {"id": 1, "title": "Cowboy Bebop"}}]}`)
+	})
+
+	res, err := GetDetailsInto[trimmedDetails](context.Background(), c, 30)
+	if err != nil {
+		t.Fatalf("Unexpected error: %q", err)
+	}
+	if res.ID != 30 || res.Rating != RatingPg13 || res.StartDate.Year != 1995 || res.RelatedAnime[0].Node.Rank != 270 || res.Recommendations[0].Node.Title != "Cowboy Bebop" {
+		t.Errorf("Unexpected anime %+v", res)
+	}
+
+	if _, err := GetDetailsInto[int](context.Background(), c, 30); err == nil {
+		t.Errorf("Expected an error when not decoding into a struct")
+	}
+}
+
+// extendedAnime adds a field to Anime that this package doesn't know about yet
+type extendedAnime struct {
+	Anime
+	Extra string `json:"some_new_field"`
+}
+
+func TestGetDetailsIntoEmbedded(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if fields := r.URL.Query().Get("fields"); !strings.HasSuffix(fields, ",some_new_field") {
+			t.Errorf("Unexpected fields %q", fields)
+		}
+		fmt.Fprint(w, `{"id": 1, "title": "Cowboy Bebop", "mean": 8.75, "some_new_field": "extra"}`)
+	})
+
+	res, err := GetDetailsInto[extendedAnime](context.Background(), c, 1)
+	if err != nil {
+		t.Fatalf("Unexpected error: %q", err)
+	}
+	if res.Extra != "extra" || res.Title != "Cowboy Bebop" || res.Mean != 8.75 {
+		t.Errorf("Unexpected anime %+v", res)
+	}
+	if !res.Requested(FieldMean) || !res.Has(FieldMean) || res.Has(FieldRank) {
+		t.Errorf("Expected presence to be tracked on the embedded anime")
+	}
+}
+
+func TestGetSeasonInto(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("fields"); got != "id,title,alternative_titles{en}" {
+			t.Errorf("Unexpected fields %q", got)
+		}
+		fmt.Fprint(w, `{"data": [{"node": {"id": 1, "title": "Cowboy Bebop", "alternative_titles": {"en": "Cowboy Bebop"}}}], "paging": {"next": "x"}}`)
+	})
+
+	res, err := GetSeasonInto[trimmedAnime](context.Background(), c, &SeasonalQuery{Year: 1998, Season: SeasonSpring})
+	if err != nil {
+		t.Fatalf("Unexpected error: %q", err)
+	}
+	if len(res.Data) != 1 || res.Data[0].Node.AlternativeTitles.En != "Cowboy Bebop" || !res.Paging.HasNext() {
+		t.Errorf("Unexpected page %+v", res)
+	}
+
+	// Detail only fields are rejected before sending
+	if _, err := GetRankingInto[trimmedDetails](context.Background(), c, &RankingQuery{}); err == nil {
+		t.Errorf("Expected an error for detail only fields")
+	}
+}
+
+func TestGetSuggestionsInto(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/anime/suggestions" || r.URL.Query().Get("fields") != "id,title,alternative_titles{en}" {
+			t.Errorf("Unexpected request %q", r.URL)
+		}
+		fmt.Fprint(w, `{"data": [{"node": {"id": 1, "title": "Cowboy Bebop", "alternative_titles": {"en": "Cowboy Bebop"}}}], "paging": {}}`)
+	})
+
+	if _, err := GetSuggestionsInto[trimmedAnime](context.Background(), c, &SuggestionsQuery{}); !errors.Is(err, ErrAuthRequired) {
+		t.Errorf("Expected %v, got %v", ErrAuthRequired, err)
+	}
+
+	c.TokenSource = auth.StaticTokenSource(&auth.Token{AccessToken: "abc"})
+	res, err := GetSuggestionsInto[trimmedAnime](context.Background(), c, &SuggestionsQuery{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %q", err)
+	}
+	if len(res.Data) != 1 || res.Data[0].Node.AlternativeTitles.En != "Cowboy Bebop" {
+		t.Errorf("Unexpected page %+v", res)
+	}
+}
+
+func TestGetUserAnimeListInto(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/users/someone/animelist" || r.URL.Query().Get("fields") != "id,title,alternative_titles{en},list_status" {
+			t.Errorf("Unexpected request %q", r.URL)
+		}
+		fmt.Fprint(w, `{"data": [{"node": {"id": 1, "title": "Cowboy Bebop", "alternative_titles": {"en": "Cowboy Bebop"}}, "list_status": {"status": "completed", "score": 10}}], "paging": {}}`)
+	})
+
+	res, err := GetUserAnimeListInto[trimmedAnime](context.Background(), c, &UserAnimeListQuery{UserName: "someone"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %q", err)
+	}
+	if len(res.Data) != 1 || res.Data[0].Node.AlternativeTitles.En != "Cowboy Bebop" || res.Data[0].ListStatus.Status != WatchStatusCompleted {
+		t.Errorf("Unexpected page %+v", res)
+	}
+}
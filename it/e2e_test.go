@@ -7,18 +7,37 @@ import (
 	"testing"
 
 	mal "github.com/fuzzylimes/malgomate"
+	"github.com/fuzzylimes/malgomate/malgomatetest"
 )
 
-func TestRanking(t *testing.T) {
+// newClient builds a client that replays the synthetic fixtures in testdata. Set MAL_RECORD (along with
+// MAL_API_KEY) to run against the live API instead, replacing the fixtures with recordings of its responses.
+func newClient(t *testing.T) *mal.Client {
+	mode := malgomatetest.ModeReplay
+	if os.Getenv("MAL_RECORD") != "" {
+		mode = malgomatetest.ModeRecord
+	}
+
+	rec := malgomatetest.NewRecorder(mode, "testdata", nil)
+	t.Cleanup(func() {
+		for _, r := range rec.Unmatched() {
+			t.Errorf("No fixture recorded for %s, re-run with MAL_RECORD=1", r)
+		}
+	})
+
 	c := mal.NewClient(os.Getenv("MAL_API_KEY"))
-	fmt.Println(c)
+	c.HTTPClient.Transport = rec
+	return c
+}
+
+func TestRanking(t *testing.T) {
+	c := newClient(t)
 	res, err := c.GetRanking(&mal.RankingQuery{})
-	fmt.Println(err)
 	if err != nil {
-		t.Errorf("Unexpected error: %q", err)
+		t.Fatalf("Unexpected error: %q", err)
 	}
 
-	if len(res.Ranking) < 1 {
+	if len(res.Data) < 1 {
 		t.Errorf("Expected non zero number of rankings")
 	}
 
@@ -27,15 +46,15 @@ func TestRanking(t *testing.T) {
 }
 
 func TestListing(t *testing.T) {
-	c := mal.NewClient(os.Getenv("MAL_API_KEY"))
+	c := newClient(t)
 	res, err := c.GetAnime(&mal.AnimeQuery{
 		Query: "Naruto",
 	})
 	if err != nil {
-		t.Errorf("Unexpected error: %q", err)
+		t.Fatalf("Unexpected error: %q", err)
 	}
 
-	if len(res.Listing) < 1 {
+	if len(res.Data) < 1 {
 		t.Errorf("Expected non zero number of rankings")
 	}
 
@@ -44,17 +63,17 @@ func TestListing(t *testing.T) {
 }
 
 func TestSeasonal(t *testing.T) {
-	c := mal.NewClient(os.Getenv("MAL_API_KEY"))
+	c := newClient(t)
 	res, err := c.GetSeason(&mal.SeasonalQuery{
 		Year:   2022,
 		Season: mal.SeasonWinter,
 		Limit:  10,
 	})
 	if err != nil {
-		t.Errorf("Unexpected error: %q", err)
+		t.Fatalf("Unexpected error: %q", err)
 	}
 
-	if len(res.Listing) < 1 {
+	if len(res.Data) < 1 {
 		t.Errorf("Expected non zero number of rankings")
 	}
 
@@ -75,7 +94,7 @@ func TestSeasonal(t *testing.T) {
 
 func TestDetails(t *testing.T) {
 	queryId := 10379
-	c := mal.NewClient(os.Getenv("MAL_API_KEY"))
+	c := newClient(t)
 	res, err := c.GetDetails(&mal.DetailsQuery{
 		Id: queryId,
 		Fields: []mal.DetailField{
@@ -84,7 +103,7 @@ func TestDetails(t *testing.T) {
 		},
 	})
 	if err != nil {
-		t.Errorf("Unexpected error: %q", err)
+		t.Fatalf("Unexpected error: %q", err)
 	}
 
 	if res.ID != queryId {
@@ -97,7 +116,7 @@ func TestDetails(t *testing.T) {
 
 func TestSubFields(t *testing.T) {
 	queryId := 10379
-	c := mal.NewClient(os.Getenv("MAL_API_KEY"))
+	c := newClient(t)
 	res, err := c.GetDetails(&mal.DetailsQuery{
 		Id: queryId,
 		Fields: []mal.DetailField{
@@ -112,7 +131,7 @@ func TestSubFields(t *testing.T) {
 		},
 	})
 	if err != nil {
-		t.Errorf("Unexpected error: %q", err)
+		t.Fatalf("Unexpected error: %q", err)
 	}
 
 	for _, v := range res.RelatedAnime {
@@ -122,7 +141,7 @@ func TestSubFields(t *testing.T) {
 	}
 
 	for _, v := range res.Recommendations {
-		if v.Node.EndDate == "" {
+		if v.Node.EndDate == nil {
 			t.Errorf("Should have returned end Date")
 		}
 	}
@@ -0,0 +1,121 @@
+package malgomate
+
+import "context"
+
+// Iterator walks through every result of a paginated query, one item at a time, lazily fetching each page as
+// it is needed. Iteration stops at the last page, after the maximum number of items, when the context is
+// cancelled, or when an error occurs.
+type Iterator[T any] struct {
+	ctx      context.Context
+	client   *Client
+	maxItems int
+	first    func(ctx context.Context) (*Page[T], error)
+
+	page  *Page[T]
+	index int
+	seen  int
+	done  bool
+	err   error
+}
+
+// Iterators for each of the endpoints supported by the Iter methods
+type (
+	ListIterator          = Iterator[Listing]
+	RankingIterator       = Iterator[Ranking]
+	UserAnimeListIterator = Iterator[UserAnimeListing]
+)
+
+// newIterator builds an Iterator that loads its first page with the provided function, and follows the
+// next links from there
+func newIterator[T any](ctx context.Context, c *Client, maxItems int, first func(ctx context.Context) (*Page[T], error)) *Iterator[T] {
+	return &Iterator[T]{ctx: ctx, client: c, maxItems: maxItems, first: first}
+}
+
+// Next advances to the next item, fetching the next page when needed. Returns false once every item has been
+// visited, the maximum number of items has been reached, or an error has occurred.
+func (it *Iterator[T]) Next() bool {
+	if it.done || it.err != nil {
+		return false
+	}
+	if it.maxItems > 0 && it.seen >= it.maxItems {
+		it.done = true
+		return false
+	}
+
+	it.index++
+	for it.page == nil || it.index >= len(it.page.Data) {
+		if it.page != nil && !it.page.Paging.HasNext() {
+			it.done = true
+			return false
+		}
+		if err := it.ctx.Err(); err != nil {
+			it.err = err
+			return false
+		}
+
+		var page *Page[T]
+		var err error
+		if it.page == nil {
+			page, err = it.first(it.ctx)
+		} else {
+			page, err = it.page.Next(it.ctx, it.client)
+		}
+		if err != nil {
+			it.err = err
+			return false
+		}
+		it.page, it.index = page, 0
+	}
+
+	it.seen++
+	return true
+}
+
+// Item returns the current item. Only valid after a call to Next has returned true.
+func (it *Iterator[T]) Item() *T {
+	return &it.page.Data[it.index]
+}
+
+// Err returns the error that stopped the iteration, if any
+func (it *Iterator[T]) Err() error {
+	return it.err
+}
+
+// IterAnime returns an iterator over every result of an anime query, lazily fetching each page as it is needed.
+// Iteration stops after maxItems results, or at the last page when maxItems is 0. Cancelling the context stops
+// the iteration, with the context's error reported by Err.
+//
+//	it := c.IterAnime(ctx, &mal.AnimeQuery{Query: "Naruto"}, 250)
+//	for it.Next() {
+//	    fmt.Println(it.Item().Node.Title)
+//	}
+//	if err := it.Err(); err != nil { ... }
+func (c *Client) IterAnime(ctx context.Context, aq *AnimeQuery, maxItems int) *ListIterator {
+	return newIterator(ctx, c, maxItems, func(ctx context.Context) (*ListPage, error) {
+		return c.GetAnimeContext(ctx, aq)
+	})
+}
+
+// IterSeason returns an iterator over every result of a seasonal query. Works the same way as IterAnime.
+func (c *Client) IterSeason(ctx context.Context, q *SeasonalQuery, maxItems int) *ListIterator {
+	return newIterator(ctx, c, maxItems, func(ctx context.Context) (*ListPage, error) {
+		return c.GetSeasonContext(ctx, q)
+	})
+}
+
+// IterRanking returns an iterator over every result of a ranking query. Works the same way as IterAnime, so
+// collecting the top 2000 ranked shows is as simple as:
+//
+//	it := c.IterRanking(ctx, &mal.RankingQuery{Limit: mal.LargeQueryLimit}, 2000)
+func (c *Client) IterRanking(ctx context.Context, r *RankingQuery, maxItems int) *RankingIterator {
+	return newIterator(ctx, c, maxItems, func(ctx context.Context) (*RankingPage, error) {
+		return c.GetRankingContext(ctx, r)
+	})
+}
+
+// IterUserAnimeList returns an iterator over every entry on a user's anime list. Works the same way as IterAnime.
+func (c *Client) IterUserAnimeList(ctx context.Context, q *UserAnimeListQuery, maxItems int) *UserAnimeListIterator {
+	return newIterator(ctx, c, maxItems, func(ctx context.Context) (*UserAnimeListPage, error) {
+		return c.GetUserAnimeListContext(ctx, q)
+	})
+}
@@ -0,0 +1,155 @@
+package malgomate
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"testing"
+)
+
+// newPagedClient serves total numbered anime across as many pages as the requested limit requires, counting the
+// pages that were fetched
+func newPagedClient(t *testing.T, total int, pages *int) *Client {
+	t.Helper()
+	var base string
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		*pages++
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+
+		data := []map[string]interface{}{}
+		for i := offset; i < offset+limit && i < total; i++ {
+			data = append(data, map[string]interface{}{
+				"node":    map[string]interface{}{"id": i + 1, "title": fmt.Sprintf("Anime %d", i+1)},
+				"ranking": map[string]interface{}{"rank": i + 1},
+			})
+		}
+		paging := map[string]string{}
+		if offset+limit < total {
+			paging["next"] = fmt.Sprintf("%s%s?limit=%d&offset=%d", base, r.URL.Path, limit, offset+limit)
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"data": data, "paging": paging})
+	})
+	base = c.BaseURL
+	return c
+}
+
+func TestIterRanking(t *testing.T) {
+	testCases := []struct {
+		total    int
+		limit    int
+		maxItems int
+		expected int
+		pages    int
+	}{
+		{0, 10, 0, 0, 1},
+		{25, 10, 0, 25, 3},
+		{30, 10, 0, 30, 3},
+		{25, 10, 15, 15, 2},
+		{25, 10, 20, 20, 2},
+		{5, 10, 100, 5, 1},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("Test case %d", i), func(t *testing.T) {
+			var pages int
+			c := newPagedClient(t, tc.total, &pages)
+
+			it := c.IterRanking(context.Background(), &RankingQuery{Limit: tc.limit}, tc.maxItems)
+			var got int
+			for it.Next() {
+				got++
+				if r := it.Item(); r.Rank.Rank != got || r.Node.ID != got {
+					t.Errorf("Expected item %d, got %+v", got, r)
+				}
+			}
+			if err := it.Err(); err != nil {
+				t.Fatalf("Unexpected error: %q", err)
+			}
+			if got != tc.expected || pages != tc.pages {
+				t.Errorf("Expected %d items over %d pages, got %d items over %d pages", tc.expected, tc.pages, got, pages)
+			}
+			if it.Next() {
+				t.Errorf("Expected a finished iterator to stay finished")
+			}
+		})
+	}
+}
+
+func TestIterAnimeAndSeason(t *testing.T) {
+	var pages int
+	c := newPagedClient(t, 12, &pages)
+
+	iterators := []*ListIterator{
+		c.IterAnime(context.Background(), &AnimeQuery{Query: "anime", Limit: 5}, 0),
+		c.IterSeason(context.Background(), &SeasonalQuery{Year: 2022, Season: SeasonWinter, Limit: 5}, 0),
+	}
+	for i, it := range iterators {
+		var got int
+		for it.Next() {
+			got++
+			if it.Item().Node.ID != got {
+				t.Errorf("Expected item %d, got %+v", got, it.Item())
+			}
+		}
+		if it.Err() != nil || got != 12 {
+			t.Errorf("Iterator %d: expected 12 items, got %d and error %v", i, got, it.Err())
+		}
+	}
+}
+
+func TestIterUserAnimeList(t *testing.T) {
+	var pages int
+	c := newPagedClient(t, 7, &pages)
+
+	it := c.IterUserAnimeList(context.Background(), &UserAnimeListQuery{UserName: "someone", Limit: 3}, 0)
+	var got int
+	for it.Next() {
+		got++
+		if it.Item().Node.ID != got {
+			t.Errorf("Expected item %d, got %+v", got, it.Item())
+		}
+	}
+	if it.Err() != nil || got != 7 || pages != 3 {
+		t.Errorf("Expected 7 items over 3 pages, got %d over %d and error %v", got, pages, it.Err())
+	}
+}
+
+func TestIteratorCancellation(t *testing.T) {
+	var pages int
+	c := newPagedClient(t, 100, &pages)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	it := c.IterRanking(ctx, &RankingQuery{Limit: 10}, 0)
+	var got int
+	for it.Next() {
+		got++
+		if got == 15 {
+			cancel()
+		}
+	}
+	if !errors.Is(it.Err(), context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", it.Err())
+	}
+	if got != 20 || pages != 2 {
+		t.Errorf("Expected iteration to stop at the end of the current page, got %d items over %d pages", got, pages)
+	}
+}
+
+func TestIteratorError(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	it := c.IterRanking(context.Background(), &RankingQuery{}, 0)
+	if it.Next() {
+		t.Errorf("Expected no items")
+	}
+	if !errors.Is(it.Err(), ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", it.Err())
+	}
+}
@@ -2,11 +2,15 @@
 package malgomate
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
-	"fmt"
+	"io"
 	"net/http"
+	"sync"
 	"time"
+
+	"github.com/fuzzylimes/malgomate/auth"
 )
 
 const (
@@ -15,11 +19,27 @@ const (
 
 // Client is the main malgomate wrapper. It holds the HTTP client, the MAL API URL, as well as your
 // MAL API key. Recommended to intialize via the NewClient constructor, but you can choose to construct
-// by hand incase you need to do some overrides/injection.
+// by hand incase you need to do some overrides/injection. Retries are disabled unless a RetryPolicy is set,
+// and requests are not throttled unless a RateLimiter is set. Setting a TokenSource switches the client over
+// to bearer token authentication. Responses are only cached when both a Cache and a CacheTTL for the
+// endpoint are set.
 type Client struct {
-	BaseURL    string
-	apiKey     string
-	HTTPClient *http.Client
+	BaseURL     string
+	apiKey      string
+	HTTPClient  *http.Client
+	Retry       *RetryPolicy
+	Limiter     RateLimiter
+	TokenSource auth.TokenSource
+	Cache       Cache
+	CacheTTL    map[EndpointKind]time.Duration
+
+	// StaleWhileRevalidate serves expired cache entries right away, while refreshing them in the background
+	StaleWhileRevalidate bool
+	// OfflineFallback serves expired cache entries when MAL can't be reached
+	OfflineFallback bool
+
+	cacheStats   cacheStats
+	revalidating sync.Map
 }
 
 // NewClient is a constructor for quickly building the malgomate client. Requires you to pass your
@@ -34,6 +54,15 @@ func NewClient(apiKey string) *Client {
 	}
 }
 
+// NewAuthClient is a constructor for a client that authenticates as a MAL user, which is required for calling
+// user scoped endpoints. Every request is sent with an "Authorization: Bearer" header using a token from the
+// provided TokenSource; use auth.Config.TokenSource to have tokens refreshed automatically before they expire.
+func NewAuthClient(ts auth.TokenSource) *Client {
+	c := NewClient("")
+	c.TokenSource = ts
+	return c
+}
+
 // Paging will be present when a response has additional result items
 type Paging struct {
 	Next     string `json:"next"`
@@ -51,51 +80,129 @@ func (p *Paging) HasPrevious() bool {
 }
 
 // GetNextPage is a helper function that will automatically retrieve the next page
-// of data, if one is present.
+// of data, if one is present. v must be a pointer to the page type being fetched.
 func (c *Client) GetNextPage(p *Paging, v interface{}) error {
+	return c.GetNextPageContext(context.Background(), p, v)
+}
+
+// GetNextPageContext is the same as GetNextPage, but the request is bound to the provided context.
+func (c *Client) GetNextPageContext(ctx context.Context, p *Paging, v interface{}) error {
 	if !p.HasNext() {
 		return errors.New("no next page to fetch")
 	}
-	req, err := http.NewRequest(http.MethodGet, p.Next, nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.Next, nil)
 	if err != nil {
 		return err
 	}
 
-	return c.sendRequest(req, &v)
-}
-
-// errorResponse is a general eror wrapper
-type errorResponse struct {
-	Error   string `json:"error"`
-	Message string `json:"message"`
+	return c.sendRequest(req, v)
 }
 
 // sendRequest handles all outgoing requests. Takes in an HTTP request and a reference
 // to the resulting object. sendRequest will make the API call, handle any error responses,
-// and decode the response message into the specified value
+// and decode the response message into the specified value. The response body is discarded
+// when value is nil. Requests that carry a body must set their own Content-Type. GET requests
+// are served from the client's Cache when their endpoint has a TTL configured. Any anime in
+// the decoded value are marked with the fields that were requested, and the fields that were
+// present in the response body.
 func (c *Client) sendRequest(req *http.Request, value interface{}) error {
-	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	if req.Header.Get("Content-Type") == "" {
+		req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	}
 	req.Header.Set("Accept", "application/json; charset=utf-8")
-	req.Header.Set("X-MAL-CLIENT-ID", c.apiKey)
 
-	res, err := c.HTTPClient.Do(req)
+	var body []byte
+	var err error
+	if ttl := c.cacheTTL(req); ttl > 0 {
+		body, err = c.fetchCached(req, ttl)
+	} else {
+		body, err = c.fetch(req)
+	}
 	if err != nil {
 		return err
 	}
+	if err := decode(body, value); err != nil {
+		return err
+	}
+
+	return markResponse(value, req.URL, body)
+}
+
+// fetch authorizes the request, makes the API call and reads back the response body
+func (c *Client) fetch(req *http.Request) ([]byte, error) {
+	if err := c.authorize(req); err != nil {
+		return nil, err
+	}
+
+	res, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
 
 	defer res.Body.Close()
 
-	if res.StatusCode >= 400 {
-		var errRes errorResponse
-		if err = json.NewDecoder(res.Body).Decode(&errRes); err == nil {
-			return errors.New(errRes.Message)
-		}
-		return fmt.Errorf("unknown error, status code: %d", res.StatusCode)
+	return io.ReadAll(res.Body)
+}
+
+// decode unmarshals a response body into the specified value, skipping it when value is nil
+func decode(body []byte, value interface{}) error {
+	if value == nil {
+		return nil
 	}
+	return json.Unmarshal(body, value)
+}
 
-	if err = json.NewDecoder(res.Body).Decode(&value); err != nil {
-		return err
+// authorize attaches credentials to the request. A bearer token is used when the client has a TokenSource,
+// otherwise the client ID is sent.
+func (c *Client) authorize(req *http.Request) error {
+	if c.TokenSource == nil {
+		req.Header.Set("X-MAL-CLIENT-ID", c.apiKey)
+		return nil
 	}
 
+	tok, err := c.TokenSource.Token(req.Context())
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+tok.AccessToken)
 	return nil
 }
+
+// do makes the API call, waiting on the client's RateLimiter before each attempt and retrying
+// GET requests according to the client's RetryPolicy. Error responses are converted into an
+// *APIError, so any response returned is a successful one.
+func (c *Client) do(req *http.Request) (*http.Response, error) {
+	attempts := 1
+	if c.Retry != nil && req.Method == http.MethodGet && c.Retry.MaxAttempts > 1 {
+		attempts = c.Retry.MaxAttempts
+	}
+
+	for attempt := 1; ; attempt++ {
+		if c.Limiter != nil {
+			if err := c.Limiter.Wait(req.Context()); err != nil {
+				return nil, err
+			}
+		}
+
+		res, err := c.HTTPClient.Do(req)
+		if err == nil && res.StatusCode >= 400 {
+			err = newAPIError(req, res)
+			res.Body.Close()
+		}
+		if err == nil {
+			return res, nil
+		}
+
+		if attempt >= attempts || !c.Retry.retryable(err) {
+			return nil, err
+		}
+
+		delay := c.Retry.delay(attempt, err)
+		if c.Retry.OnRetry != nil {
+			c.Retry.OnRetry(RetryAttempt{Attempt: attempt, Err: err, Delay: delay, Request: req})
+		}
+		if err := sleep(req.Context(), delay); err != nil {
+			return nil, err
+		}
+	}
+}
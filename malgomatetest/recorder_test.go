@@ -0,0 +1,59 @@
+package malgomatetest_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	mal "github.com/fuzzylimes/malgomate"
+	"github.com/fuzzylimes/malgomate/malgomatetest"
+)
+
+func TestRecordReplay(t *testing.T) {
+	dir := t.TempDir()
+	srv := malgomatetest.NewServer(dataset(3)...)
+	defer srv.Close()
+
+	// Record against the fake server
+	c := mal.NewClient("secret-client-id")
+	c.BaseURL = srv.URL
+	c.HTTPClient.Transport = malgomatetest.NewRecorder(malgomatetest.ModeRecord, dir, nil)
+	want, err := c.GetDetails(&mal.DetailsQuery{Id: 2, Fields: mal.DetailFields{mal.DetailTitle, mal.DetailMean}})
+	if err != nil {
+		t.Fatalf("Unexpected error: %q", err)
+	}
+
+	files, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+	if len(files) != 1 {
+		t.Fatalf("Expected 1 fixture, got %d", len(files))
+	}
+	b, _ := os.ReadFile(files[0])
+	if strings.Contains(string(b), "secret-client-id") {
+		t.Errorf("Expected client ID to be redacted from fixture")
+	}
+
+	// Replay with the server gone, a different host and the fields in another order
+	srv.Close()
+	rec := malgomatetest.NewRecorder(malgomatetest.ModeReplay, dir, nil)
+	c = mal.NewClient("")
+	c.BaseURL = "http://replay.invalid"
+	c.HTTPClient.Transport = rec
+	got, err := c.GetDetails(&mal.DetailsQuery{Id: 2, Fields: mal.DetailFields{mal.DetailMean, mal.DetailTitle}})
+	if err != nil {
+		t.Fatalf("Unexpected error: %q", err)
+	}
+	if got.ID != want.ID || got.Title != want.Title || got.Mean != want.Mean {
+		t.Errorf("Expected replayed %+v, got %+v", want, got)
+	}
+
+	// Unrecorded requests fail
+	_, err = c.GetDetails(&mal.DetailsQuery{Id: 3, Fields: mal.DetailFields{mal.DetailTitle}})
+	if !errors.Is(err, malgomatetest.ErrNoFixture) {
+		t.Errorf("Expected ErrNoFixture, got %v", err)
+	}
+	if u := rec.Unmatched(); len(u) != 1 || !strings.Contains(u[0], "/anime/3") {
+		t.Errorf("Expected unmatched request to be reported, got %v", u)
+	}
+}
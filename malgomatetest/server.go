@@ -0,0 +1,454 @@
+// Package malgomatetest provides an in-process fake of the MAL API for use in unit tests. The fake serves anime
+// from a seeded in-memory dataset, so code built on malgomate can be tested without an API key or network access.
+package malgomatetest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	mal "github.com/fuzzylimes/malgomate"
+)
+
+// defaultFields are always returned by MAL, no matter which fields were requested
+var defaultFields = []string{"id", "title", "main_picture"}
+
+// Error is a failure to inject into the responses of a Server
+type Error struct {
+	// Path is the request path to fail (e.g. "/anime/1"). Empty fails every request.
+	Path string
+	// Status is the HTTP status code to respond with
+	Status int
+	// Code and Message make up the MAL error body
+	Code    string
+	Message string
+	// Header is added to the error response, e.g. to send a Retry-After
+	Header http.Header
+	// Times is the number of requests to fail before the error is cleared. Zero fails every request.
+	Times int
+}
+
+// Server is a fake MAL API server. It emulates the anime list, details, ranking and seasonal endpoints,
+// honoring the fields, limit and offset parameters and generating paging links, the same way MAL does.
+type Server struct {
+	*httptest.Server
+
+	mu     sync.Mutex
+	anime  map[int]mal.Anime
+	errors []*Error
+}
+
+// NewServer starts a Server seeded with the provided anime. Close the server once done with it.
+func NewServer(anime ...mal.Anime) *Server {
+	s := &Server{anime: map[int]mal.Anime{}}
+	s.Seed(anime...)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/anime", s.handleList)
+	mux.HandleFunc("/anime/", s.handleAnime)
+	s.Server = httptest.NewServer(s.wrap(mux))
+	return s
+}
+
+// Client returns a malgomate Client pointed at the server
+func (s *Server) Client() *mal.Client {
+	c := mal.NewClient("malgomatetest")
+	c.BaseURL = s.URL
+	c.HTTPClient = s.Server.Client()
+	return c
+}
+
+// Seed adds anime to the dataset, replacing any existing anime with the same ID
+func (s *Server) Seed(anime ...mal.Anime) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, a := range anime {
+		s.anime[a.ID] = a
+	}
+}
+
+// InjectError makes the server fail requests with the provided error, until it has been returned e.Times times.
+// Errors are checked in the order they were injected.
+func (s *Server) InjectError(e Error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.errors = append(s.errors, &e)
+}
+
+// ClearErrors removes every injected error
+func (s *Server) ClearErrors() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.errors = nil
+}
+
+// wrap handles authentication and error injection ahead of the endpoint handlers
+func (s *Server) wrap(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeError(w, http.StatusMethodNotAllowed, "method_not_allowed", "")
+			return
+		}
+		if r.Header.Get("X-MAL-CLIENT-ID") == "" && r.Header.Get("Authorization") == "" {
+			writeError(w, http.StatusUnauthorized, "invalid_token", "")
+			return
+		}
+		if e := s.nextError(r.URL.Path); e != nil {
+			for k, v := range e.Header {
+				w.Header()[k] = v
+			}
+			writeError(w, e.Status, e.Code, e.Message)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// nextError finds the injected error that applies to a path, if there is one
+func (s *Server) nextError(path string) *Error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, e := range s.errors {
+		if e.Path != "" && e.Path != path {
+			continue
+		}
+		if e.Times > 0 {
+			e.Times--
+			if e.Times == 0 {
+				s.errors = append(s.errors[:i], s.errors[i+1:]...)
+			}
+		}
+		return e
+	}
+	return nil
+}
+
+// handleList serves GET /anime
+func (s *Server) handleList(w http.ResponseWriter, r *http.Request) {
+	q := strings.ToLower(r.URL.Query().Get("q"))
+	if q == "" {
+		writeError(w, http.StatusBadRequest, "bad_request", "invalid q")
+		return
+	}
+
+	s.writePage(w, r, mal.SmallQueryLimit, false, s.filter(func(a *mal.Anime) bool {
+		if strings.Contains(strings.ToLower(a.Title), q) {
+			return true
+		}
+		if a.AlternativeTitles != nil {
+			for _, t := range append([]string{a.AlternativeTitles.En, a.AlternativeTitles.Ja}, a.AlternativeTitles.Synonyms...) {
+				if strings.Contains(strings.ToLower(t), q) {
+					return true
+				}
+			}
+		}
+		return false
+	}, func(a, b *mal.Anime) bool { return a.ID < b.ID }))
+}
+
+// handleAnime serves everything under /anime/
+func (s *Server) handleAnime(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/anime/"), "/")
+	switch {
+	case len(parts) == 1 && parts[0] == "ranking":
+		s.handleRanking(w, r)
+	case len(parts) == 3 && parts[0] == "season":
+		s.handleSeason(w, r, parts[1], parts[2])
+	case len(parts) == 1:
+		s.handleDetails(w, r, parts[0])
+	default:
+		writeError(w, http.StatusNotFound, "not_found", "")
+	}
+}
+
+// handleDetails serves GET /anime/{id}
+func (s *Server) handleDetails(w http.ResponseWriter, r *http.Request, rawID string) {
+	id, err := strconv.Atoi(rawID)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "bad_request", "invalid anime_id")
+		return
+	}
+
+	s.mu.Lock()
+	a, ok := s.anime[id]
+	s.mu.Unlock()
+	if !ok {
+		writeError(w, http.StatusNotFound, "not_found", "")
+		return
+	}
+
+	writeJSON(w, selectFields(mustMarshal(a), parseFields(r.URL.Query().Get("fields"))))
+}
+
+// handleRanking serves GET /anime/ranking
+func (s *Server) handleRanking(w http.ResponseWriter, r *http.Request) {
+	rankingType := r.URL.Query().Get("ranking_type")
+	if rankingType == "" {
+		rankingType = string(mal.RankingAll)
+	}
+	if !mal.RankTypeQueries.IsValid(rankingType) {
+		writeError(w, http.StatusBadRequest, "bad_request", "invalid ranking_type")
+		return
+	}
+
+	// Anime without a value for the ranked property are pushed to the back
+	byValue := func(value func(*mal.Anime) int) func(a, b *mal.Anime) bool {
+		return func(a, b *mal.Anime) bool {
+			va, vb := value(a), value(b)
+			if (va == 0) != (vb == 0) {
+				return vb == 0
+			}
+			if va != vb {
+				return va < vb
+			}
+			return a.ID < b.ID
+		}
+	}
+	byRank := byValue(func(a *mal.Anime) int { return a.Rank })
+	keep := func(a *mal.Anime) bool { return true }
+	less := byRank
+	switch mal.RankingType(rankingType) {
+	case mal.RankingAiring:
+		keep = func(a *mal.Anime) bool { return a.Status == mal.AiringStatusCurrentlyAiring }
+	case mal.RankingUpcoming:
+		keep = func(a *mal.Anime) bool { return a.Status == mal.AiringStatusNotYetAired }
+		less = byValue(func(a *mal.Anime) int { return a.Popularity })
+	case mal.RankingTv, mal.RankingOva, mal.RankingMovie, mal.RankingSpecial:
+		keep = func(a *mal.Anime) bool { return string(a.MediaType) == rankingType }
+	case mal.RankingByPopularity:
+		less = byValue(func(a *mal.Anime) int { return a.Popularity })
+	case mal.RankingFavorite:
+		less = func(a, b *mal.Anime) bool { return a.NumListUsers > b.NumListUsers }
+	}
+
+	s.writePage(w, r, mal.LargeQueryLimit, true, s.filter(keep, less))
+}
+
+// handleSeason serves GET /anime/season/{year}/{season}
+func (s *Server) handleSeason(w http.ResponseWriter, r *http.Request, rawYear, season string) {
+	year, err := strconv.Atoi(rawYear)
+	if err != nil || !mal.SeasonTypeQueries.IsValid(season) {
+		writeError(w, http.StatusBadRequest, "bad_request", "invalid season")
+		return
+	}
+	sortBy := r.URL.Query().Get("sort")
+	if sortBy != "" && !mal.SeasonSortTypeQueries.IsValid(sortBy) {
+		writeError(w, http.StatusBadRequest, "bad_request", "invalid sort")
+		return
+	}
+
+	less := func(a, b *mal.Anime) bool { return a.ID < b.ID }
+	switch mal.SeasonSort(sortBy) {
+	case mal.SeasonSortScore:
+		less = func(a, b *mal.Anime) bool { return a.Mean > b.Mean }
+	case mal.SeasonSortUsers:
+		less = func(a, b *mal.Anime) bool { return a.NumListUsers > b.NumListUsers }
+	}
+
+	s.writePage(w, r, mal.LargeQueryLimit, false, s.filter(func(a *mal.Anime) bool {
+		return a.StartSeason != nil && a.StartSeason.Year == year && a.StartSeason.Season == season
+	}, less))
+}
+
+// filter returns the anime that match keep, sorted using less
+func (s *Server) filter(keep func(*mal.Anime) bool, less func(a, b *mal.Anime) bool) []mal.Anime {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var res []mal.Anime
+	for _, a := range s.anime {
+		a := a
+		if keep(&a) {
+			res = append(res, a)
+		}
+	}
+	sort.SliceStable(res, func(i, j int) bool {
+		if less(&res[i], &res[j]) {
+			return true
+		}
+		if less(&res[j], &res[i]) {
+			return false
+		}
+		return res[i].ID < res[j].ID
+	})
+	return res
+}
+
+// writePage writes out a single page of results, based on the limit and offset of the request. Ranked pages
+// include each anime's position in the full list of results.
+func (s *Server) writePage(w http.ResponseWriter, r *http.Request, maxLimit int, ranked bool, anime []mal.Anime) {
+	q := r.URL.Query()
+	limit, offset := 100, 0
+	if v := q.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > maxLimit {
+			writeError(w, http.StatusBadRequest, "bad_request", "invalid limit")
+			return
+		}
+		limit = n
+	}
+	if v := q.Get("offset"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			writeError(w, http.StatusBadRequest, "bad_request", "invalid offset")
+			return
+		}
+		offset = n
+	}
+
+	fields := parseFields(q.Get("fields"))
+	data := []map[string]json.RawMessage{}
+	for i := offset; i < offset+limit && i < len(anime); i++ {
+		item := map[string]json.RawMessage{"node": selectFields(mustMarshal(anime[i]), fields)}
+		if ranked {
+			item["ranking"] = mustMarshal(mal.Rank{Rank: i + 1})
+		}
+		data = append(data, item)
+	}
+
+	paging := mal.Paging{}
+	if offset+limit < len(anime) {
+		paging.Next = s.pageLink(r, limit, offset+limit)
+	}
+	if offset > 0 {
+		prev := offset - limit
+		if prev < 0 {
+			prev = 0
+		}
+		paging.Previous = s.pageLink(r, limit, prev)
+	}
+
+	writeJSON(w, mustMarshal(struct {
+		Data   []map[string]json.RawMessage `json:"data"`
+		Paging mal.Paging                   `json:"paging"`
+	}{data, paging}))
+}
+
+// pageLink builds the link to another page of the same query
+func (s *Server) pageLink(r *http.Request, limit, offset int) string {
+	q := r.URL.Query()
+	q.Set("limit", strconv.Itoa(limit))
+	q.Set("offset", strconv.Itoa(offset))
+	u := url.URL{Path: r.URL.Path, RawQuery: q.Encode()}
+	return s.URL + u.String()
+}
+
+// field is a requested field, along with any requested sub fields
+type field struct {
+	name string
+	sub  []field
+}
+
+// parseFields parses the fields parameter, including nested sub fields such as related_anime{id,rank}
+func parseFields(raw string) []field {
+	var fields []field
+	depth, start := 0, 0
+	add := func(f string) {
+		f = strings.TrimSpace(f)
+		if f == "" {
+			return
+		}
+		if i := strings.Index(f, "{"); i >= 0 && strings.HasSuffix(f, "}") {
+			fields = append(fields, field{name: f[:i], sub: parseFields(f[i+1 : len(f)-1])})
+			return
+		}
+		fields = append(fields, field{name: f})
+	}
+	for i, r := range raw {
+		switch r {
+		case '{':
+			depth++
+		case '}':
+			depth--
+		case ',':
+			if depth == 0 {
+				add(raw[start:i])
+				start = i + 1
+			}
+		}
+	}
+	add(raw[start:])
+	return fields
+}
+
+// selectFields trims an anime down to the default fields plus the requested fields. Sub fields are applied to
+// nested objects, and to the node of each entry in nested lists (related_anime, recommendations, etc.).
+func selectFields(raw json.RawMessage, fields []field) json.RawMessage {
+	var obj map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &obj); err != nil {
+		return raw
+	}
+
+	res := map[string]json.RawMessage{}
+	for _, name := range defaultFields {
+		if v, ok := obj[name]; ok {
+			res[name] = v
+		}
+	}
+	for _, f := range fields {
+		v, ok := obj[f.name]
+		if !ok {
+			continue
+		}
+		if len(f.sub) > 0 {
+			v = selectSubFields(v, f.sub)
+		}
+		res[f.name] = v
+	}
+	return mustMarshal(res)
+}
+
+// selectSubFields applies sub fields to a nested value
+func selectSubFields(raw json.RawMessage, fields []field) json.RawMessage {
+	var list []map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &list); err == nil {
+		for _, item := range list {
+			if node, ok := item["node"]; ok {
+				item["node"] = selectFields(node, fields)
+			}
+		}
+		return mustMarshal(list)
+	}
+
+	var obj map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &obj); err != nil {
+		return raw
+	}
+	res := map[string]json.RawMessage{}
+	for _, f := range fields {
+		if v, ok := obj[f.name]; ok {
+			if len(f.sub) > 0 {
+				v = selectSubFields(v, f.sub)
+			}
+			res[f.name] = v
+		}
+	}
+	return mustMarshal(res)
+}
+
+// mustMarshal marshals values that are known to be marshallable
+func mustMarshal(v interface{}) json.RawMessage {
+	b, err := json.Marshal(v)
+	if err != nil {
+		panic(fmt.Sprintf("malgomatetest: %v", err))
+	}
+	return b
+}
+
+// writeJSON writes a successful JSON response
+func writeJSON(w http.ResponseWriter, body json.RawMessage) {
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	w.Write(body)
+}
+
+// writeError writes a MAL style error response
+func writeError(w http.ResponseWriter, status int, code, message string) {
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	w.WriteHeader(status)
+	w.Write(mustMarshal(map[string]string{"error": code, "message": message}))
+}
@@ -0,0 +1,160 @@
+package malgomatetest_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	mal "github.com/fuzzylimes/malgomate"
+	"github.com/fuzzylimes/malgomate/malgomatetest"
+)
+
+// dataset builds a set of numbered anime, ranked in reverse order of their IDs
+func dataset(n int) []mal.Anime {
+	var anime []mal.Anime
+	for i := 1; i <= n; i++ {
+		season := mal.SeasonWinter
+		if i%2 == 0 {
+			season = mal.SeasonSpring
+		}
+		anime = append(anime, mal.Anime{
+			ID:           i,
+			Title:        fmt.Sprintf("Anime %d", i),
+			MainPicture:  &mal.MainPicture{Medium: "m.jpg", Large: "l.jpg"},
+			Synopsis:     "A test anime",
+			Mean:         float64(i),
+			Rank:         n - i + 1,
+			Popularity:   i,
+			NumListUsers: i * 10,
+			StartSeason:  &mal.StartSeason{Year: 2022, Season: string(season)},
+			AlternativeTitles: &mal.AlternativeTitles{
+				En: fmt.Sprintf("English-%d", i),
+				Ja: fmt.Sprintf("Japanese %d", i),
+			},
+			RelatedAnime: []*mal.RelatedAnime{
+				{Node: mal.Anime{ID: 100 + i, Title: "Related", Rank: 7}, RelationType: "sequel"},
+			},
+		})
+	}
+	return anime
+}
+
+func TestDetails(t *testing.T) {
+	srv := malgomatetest.NewServer(dataset(3)...)
+	defer srv.Close()
+	c := srv.Client()
+
+	testCases := []struct {
+		fields   mal.DetailFields
+		synopsis bool
+		related  bool
+	}{
+		{nil, false, false},
+		{mal.DetailFields{mal.DetailSynopsis}, true, false},
+		{mal.DetailFields{mal.DetailRelatedAnime.SubFields(&mal.DetailFields{mal.DetailRank})}, false, true},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("Test case %d", i), func(t *testing.T) {
+			res, err := c.GetDetails(&mal.DetailsQuery{Id: 2, Fields: tc.fields})
+			if err != nil {
+				t.Fatalf("Unexpected error: %q", err)
+			}
+			if res.ID != 2 || res.Title != "Anime 2" || res.MainPicture == nil {
+				t.Errorf("Expected the default fields, got %+v", res)
+			}
+			if (res.Synopsis != "") != tc.synopsis || res.Mean != 0 {
+				t.Errorf("Expected synopsis to be returned: %t, got %+v", tc.synopsis, res)
+			}
+			if (len(res.RelatedAnime) == 1) != tc.related {
+				t.Fatalf("Expected related anime to be returned: %t, got %+v", tc.related, res.RelatedAnime)
+			}
+			if tc.related && (res.RelatedAnime[0].Node.Rank != 7 || res.RelatedAnime[0].RelationType != "sequel") {
+				t.Errorf("Expected related anime sub fields, got %+v", res.RelatedAnime[0])
+			}
+		})
+	}
+
+	if _, err := c.GetDetails(&mal.DetailsQuery{Id: 42}); !errors.Is(err, mal.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestList(t *testing.T) {
+	srv := malgomatetest.NewServer(dataset(25)...)
+	defer srv.Close()
+	c := srv.Client()
+
+	res, err := c.GetAnime(&mal.AnimeQuery{Query: "english-1", Limit: 5, Fields: mal.QueryFields{mal.FieldAlternativeTitles}})
+	if err != nil {
+		t.Fatalf("Unexpected error: %q", err)
+	}
+	// Anime 1 and 10 through 19, across 3 pages
+	if len(res.Data) != 5 || res.Data[0].Node.ID != 1 || res.Data[1].Node.ID != 10 || res.Data[0].Node.AlternativeTitles == nil {
+		t.Errorf("Unexpected page %+v", res.Data)
+	}
+	if !res.Paging.HasNext() || res.Paging.HasPrevious() {
+		t.Errorf("Unexpected paging %+v", res.Paging)
+	}
+
+	next, err := res.Next(context.Background(), c)
+	if err != nil {
+		t.Fatalf("Unexpected error: %q", err)
+	}
+	if len(next.Data) != 5 || next.Data[0].Node.ID != 14 || !next.Paging.HasNext() || !next.Paging.HasPrevious() {
+		t.Errorf("Unexpected next page %+v", next)
+	}
+}
+
+func TestRankingAndSeason(t *testing.T) {
+	srv := malgomatetest.NewServer(dataset(25)...)
+	defer srv.Close()
+	c := srv.Client()
+
+	var ids []int
+	it := c.IterRanking(context.Background(), &mal.RankingQuery{Limit: 10}, 0)
+	for it.Next() {
+		if it.Item().Rank.Rank != len(ids)+1 {
+			t.Errorf("Unexpected ranking %+v", it.Item().Rank)
+		}
+		ids = append(ids, it.Item().Node.ID)
+	}
+	if it.Err() != nil || len(ids) != 25 || ids[0] != 25 || ids[24] != 1 {
+		t.Errorf("Unexpected ranking %v (%v)", ids, it.Err())
+	}
+
+	season, err := c.GetSeason(&mal.SeasonalQuery{Year: 2022, Season: mal.SeasonSpring, Sort: mal.SeasonSortUsers})
+	if err != nil {
+		t.Fatalf("Unexpected error: %q", err)
+	}
+	if len(season.Data) != 12 || season.Data[0].Node.ID != 24 {
+		t.Errorf("Unexpected season %+v", season.Data)
+	}
+}
+
+func TestInjectError(t *testing.T) {
+	srv := malgomatetest.NewServer(dataset(1)...)
+	defer srv.Close()
+	c := srv.Client()
+	c.Retry = &mal.RetryPolicy{MaxAttempts: 3, RetryableStatusCodes: []int{http.StatusTooManyRequests}}
+
+	retryAfter := http.Header{"Retry-After": {"0"}}
+	srv.InjectError(malgomatetest.Error{Path: "/anime/1", Status: http.StatusTooManyRequests, Header: retryAfter, Times: 2})
+	if _, err := c.GetDetails(&mal.DetailsQuery{Id: 1}); err != nil {
+		t.Errorf("Expected the request to succeed after retries, got %v", err)
+	}
+
+	srv.InjectError(malgomatetest.Error{Status: http.StatusInternalServerError, Code: "oops", Message: "boom"})
+	_, err := c.GetDetails(&mal.DetailsQuery{Id: 1})
+	var apiErr *mal.APIError
+	if !errors.As(err, &apiErr) || apiErr.Code != "oops" || apiErr.Message != "boom" {
+		t.Errorf("Expected the injected error, got %v", err)
+	}
+
+	srv.ClearErrors()
+	if _, err := c.GetDetails(&mal.DetailsQuery{Id: 1}); err != nil {
+		t.Errorf("Unexpected error: %q", err)
+	}
+}
@@ -0,0 +1,270 @@
+package malgomate
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// Manga are the general manga response objects
+type Manga struct {
+	ID                int                     `json:"id"`
+	Title             string                  `json:"title"`
+	MainPicture       *MainPicture            `json:"main_picture,omitempty"`
+	AlternativeTitles *AlternativeTitles      `json:"alternative_titles,omitempty"`
+	StartDate         *PartialDate            `json:"start_date,omitempty"`
+	EndDate           *PartialDate            `json:"end_date,omitempty"`
+	Synopsis          string                  `json:"synopsis,omitempty"`
+	Mean              float64                 `json:"mean,omitempty"`
+	Rank              int                     `json:"rank,omitempty"`
+	Popularity        int                     `json:"popularity,omitempty"`
+	NumListUsers      int                     `json:"num_list_users,omitempty"`
+	NumScoringUsers   int                     `json:"num_scoring_users,omitempty"`
+	Nsfw              Nsfw                    `json:"nsfw,omitempty"`
+	Genres            []*Genres               `json:"genres,omitempty"`
+	CreatedAt         *Timestamp              `json:"created_at,omitempty"`
+	UpdatedAt         *Timestamp              `json:"updated_at,omitempty"`
+	MediaType         MangaMediaType          `json:"media_type,omitempty"`
+	Status            PublishingStatus        `json:"status,omitempty"`
+	NumVolumes        int                     `json:"num_volumes,omitempty"`
+	NumChapters       int                     `json:"num_chapters,omitempty"`
+	Authors           []*MangaAuthor          `json:"authors,omitempty"`
+	Pictures          []*Pictures             `json:"pictures,omitempty"`
+	Background        string                  `json:"background,omitempty"`
+	RelatedAnime      []*RelatedAnime         `json:"related_anime,omitempty"`
+	RelatedManga      []*RelatedManga         `json:"related_manga,omitempty"`
+	Recommendations   []*MangaRecommendations `json:"recommendations,omitempty"`
+	Serialization     []*Serialization        `json:"serialization,omitempty"`
+	MyListStatus      *MangaListStatus        `json:"my_list_status,omitempty"`
+}
+
+// MangaListStatus is the state of a manga on a user's list
+type MangaListStatus struct {
+	Status          ReadStatus   `json:"status,omitempty"`
+	Score           int          `json:"score"`
+	NumVolumesRead  int          `json:"num_volumes_read"`
+	NumChaptersRead int          `json:"num_chapters_read"`
+	IsRereading     bool         `json:"is_rereading"`
+	UpdatedAt       *Timestamp   `json:"updated_at,omitempty"`
+	StartDate       *PartialDate `json:"start_date,omitempty"`
+	FinishDate      *PartialDate `json:"finish_date,omitempty"`
+	Priority        int          `json:"priority,omitempty"`
+	NumTimesReread  int          `json:"num_times_reread,omitempty"`
+	RereadValue     int          `json:"reread_value,omitempty"`
+	Tags            []string     `json:"tags,omitempty"`
+	Comments        string       `json:"comments,omitempty"`
+}
+
+// JSON is a helper function that converts a manga object to a JSON string
+func (m *Manga) JSON() (string, error) {
+	if s, err := json.MarshalIndent(m, "", "  "); err == nil {
+		return string(s), err
+	} else {
+		return "", err
+	}
+}
+
+// MangaRanking is a wrapper object for manga objects and their rankings. Similar to MangaListing,
+// just with a Rank property.
+type MangaRanking struct {
+	Node Manga `json:"node"`
+	Rank Rank  `json:"ranking"`
+}
+
+// MangaListing is a wrapper object for manga objects
+type MangaListing struct {
+	Node Manga `json:"node"`
+}
+
+// MangaAuthor is a person that worked on the manga, along with their role (story, art, etc.)
+type MangaAuthor struct {
+	Node Person `json:"node"`
+	Role string `json:"role,omitempty"`
+}
+
+// Person is a person on MAL
+type Person struct {
+	ID        int    `json:"id"`
+	FirstName string `json:"first_name,omitempty"`
+	LastName  string `json:"last_name,omitempty"`
+}
+
+// Serialization is a magazine that the manga was published in
+type Serialization struct {
+	Node Magazine `json:"node"`
+	Role string   `json:"role,omitempty"`
+}
+
+// Magazine is a manga magazine
+type Magazine struct {
+	ID   int    `json:"id"`
+	Name string `json:"name,omitempty"`
+}
+
+// RelatedManga are manga that MAL users have said are related to the associated anime or manga
+type RelatedManga struct {
+	Node                  Manga  `json:"node,omitempty"`
+	RelationType          string `json:"relation_type,omitempty"`
+	RelationTypeFormatted string `json:"relation_type_formatted,omitempty"`
+}
+
+// MangaRecommendations are manga that MAL users have marked as being similar to the
+// associated manga
+type MangaRecommendations struct {
+	Node               Manga `json:"node,omitempty"`
+	NumRecommendations int   `json:"num_recommendations,omitempty"`
+}
+
+// MangaQuery is used to perform a general manga query. Query must be set. Supports fields of the QueryField type
+type MangaQuery struct {
+	Query  string
+	Limit  int
+	Offset int
+	Fields QueryFields
+}
+
+// MangaDetailsQuery is used to query specific manga by their MAL Ids. The Id must be set. Supports fields of the
+// DetailField type
+type MangaDetailsQuery struct {
+	Id     int
+	Fields DetailFields
+}
+
+// MangaRankingQuery is used to query for manga rankings. Supports fields of the QueryField type
+type MangaRankingQuery struct {
+	RankingType MangaRankingType
+	Limit       int
+	Offset      int
+	Fields      QueryFields
+}
+
+// GetMangaDetails retrieves specifics for a given MAL manga Id.
+func (c *Client) GetMangaDetails(dq *MangaDetailsQuery) (*Manga, error) {
+	return c.GetMangaDetailsContext(context.Background(), dq)
+}
+
+// GetMangaDetailsContext is the same as GetMangaDetails, but the request is bound to the provided context.
+// Cancelling the context aborts the request.
+func (c *Client) GetMangaDetailsContext(ctx context.Context, dq *MangaDetailsQuery) (*Manga, error) {
+	// Check for required values
+	if dq.Id == 0 {
+		return nil, errors.New("missing required parameter: Id must be set")
+	}
+	// Handle defaults
+	if len(dq.Fields) == 0 {
+		dq.Fields = BasicDetailQuery
+	}
+
+	if err := dq.Fields.validate(EndpointDetails, mediaManga); err != nil {
+		return nil, err
+	}
+
+	queryFields := dq.Fields.ToString()
+	queryString := fmt.Sprintf("%s/manga/%d?fields=%s", c.BaseURL, dq.Id, queryFields)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, queryString, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res := Manga{}
+	if err := c.sendRequest(req, &res); err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+// GetManga queries all manga based on a provided string. These queries return a paged list of responses containing
+// the fields specified in the intial request object. The query string must be provided. If not included, the
+// following default values will be used:
+//   - Limit - 100 (max 100)
+//   - Offset - 0
+//   - Fields - "id,title,main_picture"
+func (c *Client) GetManga(mq *MangaQuery) (*MangaListPage, error) {
+	return c.GetMangaContext(context.Background(), mq)
+}
+
+// GetMangaContext is the same as GetManga, but the request is bound to the provided context. Cancelling the
+// context aborts the request.
+func (c *Client) GetMangaContext(ctx context.Context, mq *MangaQuery) (*MangaListPage, error) {
+	// Check for required values
+	if mq.Query == "" {
+		return nil, errors.New("missing required parameter: Query must be set")
+	}
+	// Handle defaults
+	if mq.Limit == 0 {
+		mq.Limit = 100
+	} else if mq.Limit > SmallQueryLimit {
+		mq.Limit = SmallQueryLimit
+	}
+	if len(mq.Fields) == 0 {
+		mq.Fields = BasicFieldQuery
+	}
+
+	if err := mq.Fields.validate(EndpointList, mediaManga); err != nil {
+		return nil, err
+	}
+
+	queryFields := mq.Fields.ToString()
+	queryString := fmt.Sprintf("%s/manga?q=%s&limit=%d&offset=%d&fields=%s", c.BaseURL, url.QueryEscape(mq.Query), mq.Limit, mq.Offset, queryFields)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, queryString, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res := MangaListPage{}
+	if err := c.sendRequest(req, &res); err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+// GetMangaRanking queries all manga based on rankings on MAL. These queries return a paged list of responses
+// containing the fields specified in the intial request object. Rankings are returned based on the provided
+// MangaRankingType value. If not included, the following default values will be used:
+//   - RankingType - "all"
+//   - Limit - 100 (max 500)
+//   - Offset - 0
+//   - Fields - "id,title,main_picture"
+func (c *Client) GetMangaRanking(r *MangaRankingQuery) (*MangaRankingPage, error) {
+	return c.GetMangaRankingContext(context.Background(), r)
+}
+
+// GetMangaRankingContext is the same as GetMangaRanking, but the request is bound to the provided context.
+// Cancelling the context aborts the request.
+func (c *Client) GetMangaRankingContext(ctx context.Context, r *MangaRankingQuery) (*MangaRankingPage, error) {
+	// Handle defaults
+	if r.RankingType == "" {
+		r.RankingType = MangaRankingAll
+	}
+	if r.Limit == 0 {
+		r.Limit = 100
+	} else if r.Limit > LargeQueryLimit {
+		r.Limit = LargeQueryLimit
+	}
+	if len(r.Fields) == 0 {
+		r.Fields = BasicFieldQuery
+	}
+
+	if err := r.Fields.validate(EndpointRanking, mediaManga); err != nil {
+		return nil, err
+	}
+
+	queryFields := r.Fields.ToString()
+	queryString := fmt.Sprintf("%s/manga/ranking?ranking_type=%s&limit=%d&offset=%d&fields=%s", c.BaseURL, r.RankingType, r.Limit, r.Offset, queryFields)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, queryString, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res := MangaRankingPage{}
+	if err := c.sendRequest(req, &res); err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
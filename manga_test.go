@@ -0,0 +1,96 @@
+package malgomate
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestGetMangaDetails(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/manga/2" || r.URL.Query().Get("fields") != "num_volumes,authors{first_name,last_name},serialization,related_manga" {
+			t.Errorf("Unexpected request %q", r.URL)
+		}
+		fmt.Fprint(w, `{
+			"id": 2,
+			"title": "Berserk",
+			"num_volumes": 0,
+			"num_chapters": 0,
+			"authors": [{"node": {"id": 1868, "first_name": "Kentarou", "last_name": "Miura"}, "role": "Story & Art"}],
+			"serialization": [{"node": {"id": 2, "name": "Young Animal"}}],
+			"related_manga": [{"node": {"id": 92299, "title": "Berserk: Shinen no Kami 2"}, "relation_type": "side_story", "relation_type_formatted": "Side story"}]
+		}`)
+	})
+
+	res, err := c.GetMangaDetails(&MangaDetailsQuery{
+		Id: 2,
+		Fields: DetailFields{
+			DetailNumVolumes,
+			DetailAuthors.SubFields(&DetailFields{"first_name", "last_name"}),
+			DetailSerialization,
+			DetailRelatedManga,
+		},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %q", err)
+	}
+
+	if len(res.Authors) != 1 || res.Authors[0].Node.LastName != "Miura" || res.Authors[0].Role != "Story & Art" {
+		t.Errorf("Unexpected authors %+v", res.Authors)
+	}
+	if len(res.Serialization) != 1 || res.Serialization[0].Node.Name != "Young Animal" {
+		t.Errorf("Unexpected serialization %+v", res.Serialization)
+	}
+	if len(res.RelatedManga) != 1 || res.RelatedManga[0].Node.ID != 92299 || res.RelatedManga[0].RelationType != "side_story" {
+		t.Errorf("Unexpected related manga %+v", res.RelatedManga)
+	}
+}
+
+func TestAnimeRelatedManga(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id": 33, "title": "Berserk", "related_manga": [{"node": {"id": 2, "title": "Berserk"}, "relation_type": "adaptation"}]}`)
+	})
+
+	res, err := c.GetDetails(&DetailsQuery{Id: 33, Fields: DetailFields{DetailRelatedManga}})
+	if err != nil {
+		t.Fatalf("Unexpected error: %q", err)
+	}
+	if len(res.RelatedManga) != 1 || res.RelatedManga[0].Node.Title != "Berserk" || res.RelatedManga[0].RelationType != "adaptation" {
+		t.Errorf("Unexpected related manga %+v", res.RelatedManga)
+	}
+}
+
+func TestGetMangaRanking(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/manga/ranking" || r.URL.Query().Get("ranking_type") != "manhwa" || r.URL.Query().Get("limit") != "500" {
+			t.Errorf("Unexpected request %q", r.URL)
+		}
+		fmt.Fprint(w, `{"data": [{"node": {"id": 1, "title": "Solo Leveling"}, "ranking": {"rank": 1}}], "paging": {}}`)
+	})
+
+	res, err := c.GetMangaRanking(&MangaRankingQuery{RankingType: MangaRankingManhwa, Limit: 1000})
+	if err != nil {
+		t.Fatalf("Unexpected error: %q", err)
+	}
+	if len(res.Data) != 1 || res.Data[0].Rank.Rank != 1 || res.Paging.HasNext() {
+		t.Errorf("Unexpected ranking page %+v", res)
+	}
+}
+
+func TestMangaMyListStatus(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("fields"); got != "my_list_status" {
+			t.Errorf("Unexpected fields %q", got)
+		}
+		fmt.Fprint(w, `{"id": 2, "title": "Berserk", "my_list_status": {"status": "reading", "score": 9, "num_volumes_read": 3, "num_chapters_read": 40, "is_rereading": false, "updated_at": "2022-01-02T03:04:05+00:00"}}`)
+	})
+
+	res, err := c.GetMangaDetails(&MangaDetailsQuery{Id: 2, Fields: DetailFields{FieldMyListStatus}})
+	if err != nil {
+		t.Fatalf("Unexpected error: %q", err)
+	}
+	ls := res.MyListStatus
+	if ls == nil || ls.Status != ReadStatusReading || ls.Score != 9 || ls.NumChaptersRead != 40 || ls.UpdatedAt == nil {
+		t.Errorf("Unexpected list status %+v", ls)
+	}
+}
@@ -0,0 +1,67 @@
+package malgomate
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+)
+
+// Page is a paginated response page. Data holds the results on the page, and Paging holds the links to the
+// pages around it. Every paginated endpoint returns a Page of its own result type (ListPage, RankingPage, etc.).
+type Page[T any] struct {
+	Data   []T    `json:"data"`
+	Paging Paging `json:"paging"`
+}
+
+// Paginated response pages for each of the paginated endpoints
+type (
+	ListPage          = Page[Listing]
+	RankingPage       = Page[Ranking]
+	UserAnimeListPage = Page[UserAnimeListing]
+	MangaListPage     = Page[MangaListing]
+	MangaRankingPage  = Page[MangaRanking]
+	ForumTopicsPage   = Page[ForumTopic]
+)
+
+// JSON is a helper function converts a Page to a JSON string
+func (p *Page[T]) JSON() (string, error) {
+	if s, err := json.MarshalIndent(p, "", "  "); err == nil {
+		return string(s), err
+	} else {
+		return "", err
+	}
+}
+
+// Next retrieves the next page of results, if one is present
+func (p *Page[T]) Next(ctx context.Context, c *Client) (*Page[T], error) {
+	if !p.Paging.HasNext() {
+		return nil, errors.New("no next page to fetch")
+	}
+	return GetPageQS[T](ctx, c, p.Paging.Next)
+}
+
+// Previous retrieves the previous page of results, if one is present
+func (p *Page[T]) Previous(ctx context.Context, c *Client) (*Page[T], error) {
+	if !p.Paging.HasPrevious() {
+		return nil, errors.New("no previous page to fetch")
+	}
+	return GetPageQS[T](ctx, c, p.Paging.Previous)
+}
+
+// GetPageQS performs a query based on a provided query string. Allows queries to be constructed elsewhere,
+// such as the frontend or from a previous/next link. The type parameter is the result type of the endpoint
+// that the query string points at, e.g. GetPageQS[Ranking] for a ranking query.
+func GetPageQS[T any](ctx context.Context, c *Client, qs string) (*Page[T], error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, qs, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res := Page[T]{}
+	if err := c.sendRequest(req, &res); err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
@@ -0,0 +1,68 @@
+package malgomate
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestPageNavigation(t *testing.T) {
+	var pages int
+	c := newPagedClient(t, 25, &pages)
+
+	first, err := GetPageQS[MangaRanking](context.Background(), c, c.BaseURL+"/manga/ranking?limit=10&offset=0")
+	if err != nil {
+		t.Fatalf("Unexpected error: %q", err)
+	}
+	if _, err := first.Previous(context.Background(), c); err == nil {
+		t.Errorf("Expected an error when there is no previous page")
+	}
+
+	testCases := []struct {
+		items int
+		first int
+		last  bool
+	}{
+		{10, 11, false},
+		{5, 21, true},
+	}
+
+	page := first
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("Test case %d", i), func(t *testing.T) {
+			next, err := page.Next(context.Background(), c)
+			if err != nil {
+				t.Fatalf("Unexpected error: %q", err)
+			}
+			if len(next.Data) != tc.items || next.Data[0].Node.ID != tc.first || next.Data[0].Rank.Rank != tc.first {
+				t.Errorf("Unexpected page %+v", next.Data)
+			}
+			if next.Paging.HasNext() == tc.last {
+				t.Errorf("Expected HasNext to be %t", !tc.last)
+			}
+			page = next
+		})
+	}
+
+	if _, err := page.Next(context.Background(), c); err == nil {
+		t.Errorf("Expected an error when there is no next page")
+	}
+}
+
+func TestPagePrevious(t *testing.T) {
+	var base string
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"data": [{"id": 1, "title": "Topic %s"}], "paging": {"previous": "%s/forum/topics?offset=0"}}`, r.URL.Query().Get("offset"), base)
+	})
+	base = c.BaseURL
+
+	page := &ForumTopicsPage{Paging: Paging{Previous: c.BaseURL + "/forum/topics?offset=100"}}
+	prev, err := page.Previous(context.Background(), c)
+	if err != nil {
+		t.Fatalf("Unexpected error: %q", err)
+	}
+	if len(prev.Data) != 1 || prev.Data[0].Title != "Topic 100" || !prev.Paging.HasPrevious() {
+		t.Errorf("Unexpected page %+v", prev)
+	}
+}
@@ -0,0 +1,162 @@
+package malgomate
+
+import (
+	"encoding/json"
+	"net/url"
+)
+
+// defaultFields are returned by MAL for every anime, whether they were asked for or not
+var defaultFields = Fields{FieldID, FieldTitle, FieldMainPicture}
+
+// requestMarker is implemented by responses holding anime, so that they can be marked with the fields that
+// were requested and the fields present in the JSON they were decoded from. Presence is worked out from the
+// response body rather than by giving Anime its own UnmarshalJSON, which would be promoted to (and take over the
+// decoding of) any struct embedding Anime.
+type requestMarker interface {
+	// marksAnime reports whether there are any anime to mark, so that the body is only decoded again when needed
+	marksAnime() bool
+	// markRequested marks the anime with the requested fields, given the JSON object they were decoded from
+	markRequested(fields Fields, obj map[string]interface{})
+}
+
+// markResponse marks the anime held by value with the fields requested by the request URL, and the fields
+// present in the response body. The body is decoded once more, in a single pass, into a tree that is walked
+// alongside value.
+func markResponse(value interface{}, u *url.URL, body []byte) error {
+	m, ok := value.(requestMarker)
+	if !ok || !m.marksAnime() {
+		return nil
+	}
+	var obj map[string]interface{}
+	if err := json.Unmarshal(body, &obj); err != nil {
+		return err
+	}
+	m.markRequested(requestedFields(u), obj)
+	return nil
+}
+
+// requestedFields reads the fields asked for by a request URL
+func requestedFields(u *url.URL) Fields {
+	var fields Fields
+	if raw := u.Query().Get("fields"); raw != "" {
+		for _, f := range splitFields(raw) {
+			fields = append(fields, Field(f))
+		}
+	}
+	return fields
+}
+
+// presentFields reads the fields of a JSON object that have a value
+func presentFields(obj map[string]interface{}) map[Field]bool {
+	present := make(map[Field]bool, len(obj))
+	for k, v := range obj {
+		if v != nil {
+			present[Field(k)] = true
+		}
+	}
+	return present
+}
+
+// nodeAt returns the node held by the item at index i of a JSON list, or nil when there isn't one
+func nodeAt(items interface{}, i int) map[string]interface{} {
+	list, _ := items.([]interface{})
+	if i >= len(list) {
+		return nil
+	}
+	item, _ := list[i].(map[string]interface{})
+	node, _ := item["node"].(map[string]interface{})
+	return node
+}
+
+// Has checks to see if the field was present in the response the anime was decoded from. A field that was
+// requested but isn't present has no value on MAL, such as the mean score of an anime nobody has scored yet,
+// which sets it apart from a field that is zero because it wasn't asked for. Always false for anime that
+// weren't returned by the Client.
+func (a *Anime) Has(f Field) bool {
+	return a.present[f.Name()]
+}
+
+// Requested checks to see if the field was asked for by the request the anime was returned from. The id,
+// title and main_picture fields are always returned, so count as requested. Always false for anime that
+// weren't returned by the Client.
+func (a *Anime) Requested(f Field) bool {
+	if a.requested == nil {
+		return false
+	}
+	name := f.Name()
+	return a.requested.contains(name) || defaultFields.contains(name)
+}
+
+// marksAnime implements requestMarker
+func (a *Anime) marksAnime() bool { return true }
+
+// markRequested marks the anime with the fields requested for it and the fields present in its JSON, passing
+// any sub fields down to its related anime and recommendations
+func (a *Anime) markRequested(fields Fields, obj map[string]interface{}) {
+	a.requested = append(Fields{}, fields...)
+	a.present = presentFields(obj)
+
+	sub := map[Field]Fields{}
+	for _, f := range fields {
+		if name, s, err := f.parse(); err == nil {
+			sub[name] = s
+		}
+	}
+	for i, r := range a.RelatedAnime {
+		if r != nil {
+			r.Node.markRequested(sub[FieldRelatedAnime], nodeAt(obj[string(FieldRelatedAnime)], i))
+		}
+	}
+	for i, r := range a.Recommendations {
+		if r != nil {
+			r.Node.markRequested(sub[FieldRecommendations], nodeAt(obj[string(FieldRecommendations)], i))
+		}
+	}
+}
+
+// marksAnime implements requestMarker
+func (l *Listing) marksAnime() bool { return true }
+
+// markRequested marks the listed anime with the requested fields
+func (l *Listing) markRequested(fields Fields, obj map[string]interface{}) {
+	node, _ := obj["node"].(map[string]interface{})
+	l.Node.markRequested(fields, node)
+}
+
+// marksAnime implements requestMarker
+func (r *Ranking) marksAnime() bool { return true }
+
+// markRequested marks the ranked anime with the requested fields
+func (r *Ranking) markRequested(fields Fields, obj map[string]interface{}) {
+	node, _ := obj["node"].(map[string]interface{})
+	r.Node.markRequested(fields, node)
+}
+
+// marksAnime implements requestMarker
+func (l *UserAnimeListing) marksAnime() bool { return true }
+
+// markRequested marks the anime on the user's list with the requested fields
+func (l *UserAnimeListing) markRequested(fields Fields, obj map[string]interface{}) {
+	node, _ := obj["node"].(map[string]interface{})
+	l.Node.markRequested(fields, node)
+}
+
+// marksAnime reports whether the items on the page hold anime, checked once against the zero value of T
+func (p *Page[T]) marksAnime() bool {
+	var zero T
+	m, ok := interface{}(&zero).(requestMarker)
+	return ok && m.marksAnime()
+}
+
+// markRequested marks every item on the page with the requested fields. Only called for pages of items holding
+// anime.
+func (p *Page[T]) markRequested(fields Fields, obj map[string]interface{}) {
+	data, _ := obj["data"].([]interface{})
+	for i := range p.Data {
+		var item map[string]interface{}
+		if i < len(data) {
+			item, _ = data[i].(map[string]interface{})
+		}
+		interface{}(&p.Data[i]).(requestMarker).markRequested(fields, item)
+	}
+}
@@ -0,0 +1,143 @@
+package malgomate
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestAnimeHas(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id": 1, "title": "Cowboy Bebop", "mean": 0, "rank": null, "related_anime": [{"node": {"id": 5, "num_episodes": 1}}]}`)
+	})
+	a, err := c.GetDetails(&DetailsQuery{Id: 1})
+	if err != nil {
+		t.Fatalf("Unexpected error: %q", err)
+	}
+
+	var decoded Anime
+	if err := json.Unmarshal([]byte(`{"id": 1, "mean": 8.8}`), &decoded); err != nil {
+		t.Fatalf("Unexpected error: %q", err)
+	}
+
+	testCases := []struct {
+		a        *Anime
+		field    Field
+		expected bool
+	}{
+		{a, FieldID, true},
+		{a, FieldMean, true},
+		{a, FieldRank, false},
+		{a, FieldNumEpisodes, false},
+		{a, FieldRelatedAnime.SubFields(&Fields{FieldNumEpisodes}), true},
+		{&a.RelatedAnime[0].Node, FieldNumEpisodes, true},
+		{&a.RelatedAnime[0].Node, FieldTitle, false},
+		{&Anime{ID: 1, Mean: 8.8}, FieldMean, false},
+		{&decoded, FieldMean, false},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("Test case %d", i), func(t *testing.T) {
+			if got := tc.a.Has(tc.field); got != tc.expected {
+				t.Errorf("Expected %t, got %t", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestAnimeRequested(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id": 1, "title": "Cowboy Bebop", "num_episodes": 26,
+			"related_anime": [{"node": {"id": 5, "title": "Cowboy Bebop: Tengoku no Tobira", "rank": 190}}],
+			"recommendations": [{"node": {"id": 205, "title": "Samurai Champloo"}}]}`)
+	})
+
+	a, err := c.GetDetails(&DetailsQuery{Id: 1, Fields: Fields{
+		FieldNumEpisodes,
+		FieldMean,
+		FieldRelatedAnime.SubFields(&Fields{FieldRank}),
+		FieldRecommendations,
+	}})
+	if err != nil {
+		t.Fatalf("Unexpected error: %q", err)
+	}
+
+	testCases := []struct {
+		a                  *Anime
+		field              Field
+		requested, present bool
+	}{
+		{a, FieldTitle, true, true},
+		{a, FieldNumEpisodes, true, true},
+		// Unscored rather than not asked for
+		{a, FieldMean, true, false},
+		{a, FieldRank, false, false},
+		{&a.RelatedAnime[0].Node, FieldRank, true, true},
+		{&a.RelatedAnime[0].Node, FieldMean, false, false},
+		{&a.Recommendations[0].Node, FieldTitle, true, true},
+		{&a.Recommendations[0].Node, FieldRank, false, false},
+		{&Anime{}, FieldID, false, false},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("Test case %d", i), func(t *testing.T) {
+			if got := tc.a.Requested(tc.field); got != tc.requested {
+				t.Errorf("Expected requested %t, got %t", tc.requested, got)
+			}
+			if got := tc.a.Has(tc.field); got != tc.present {
+				t.Errorf("Expected present %t, got %t", tc.present, got)
+			}
+		})
+	}
+}
+
+func TestPageRequested(t *testing.T) {
+	var base string
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("offset") == "1" {
+			fmt.Fprint(w, `{"data": [{"node": {"id": 2, "title": "Trigun"}, "ranking": {"rank": 2}}], "paging": {}}`)
+			return
+		}
+		fmt.Fprintf(w, `{"data": [{"node": {"id": 1, "title": "Cowboy Bebop", "mean": 8.8}, "ranking": {"rank": 1}}], "paging": {"next": "%s/anime/ranking?offset=1&limit=1&fields=mean,rank"}}`, base)
+	})
+	base = c.BaseURL
+
+	res, err := c.GetRanking(&RankingQuery{Limit: 1, Fields: Fields{FieldMean}})
+	if err != nil {
+		t.Fatalf("Unexpected error: %q", err)
+	}
+	if a := res.Data[0].Node; !a.Requested(FieldMean) || a.Requested(FieldRank) || !a.Has(FieldMean) {
+		t.Errorf("Expected mean to be requested and present")
+	}
+
+	next, err := res.Next(context.Background(), c)
+	if err != nil {
+		t.Fatalf("Unexpected error: %q", err)
+	}
+	if a := next.Data[0].Node; !a.Requested(FieldMean) || !a.Requested(FieldRank) || a.Has(FieldMean) {
+		t.Errorf("Expected next page to be marked from its paging link")
+	}
+}
+
+func TestPageMarksAnime(t *testing.T) {
+	testCases := []struct {
+		m        requestMarker
+		expected bool
+	}{
+		{&Page[Listing]{}, true},
+		{&Page[Ranking]{}, true},
+		{&Page[UserAnimeListing]{}, true},
+		{&Page[Anime]{}, true},
+		{&Page[MangaListing]{}, false},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("Test case %d", i), func(t *testing.T) {
+			if got := tc.m.marksAnime(); got != tc.expected {
+				t.Errorf("Expected %t, got %t", tc.expected, got)
+			}
+		})
+	}
+}
@@ -1,10 +1,5 @@
 package malgomate
 
-import (
-	"fmt"
-	"strings"
-)
-
 // Season is the name of the season when a show aired
 type Season string
 
@@ -14,52 +9,26 @@ type SeasonSort string
 // RankingType is the type by which to retrieve ranking details
 type RankingType string
 
-// QueryField are field names to be returned during a query. Used for everything except for detail queries.
-type QueryField string
-
-// QueryFields are a collection of QueryField
-type QueryFields []QueryField
-
-// ToString converts a slice of QueryField into a comma separate string of fields
-func (q QueryFields) ToString() string {
-	var sb strings.Builder
-	for i, str := range q {
-		sb.WriteString(string(str))
-		if i < len(q)-1 {
-			sb.WriteString(",")
-		}
-	}
-	return sb.String()
-}
-
-// DetailField are field names to be returned during a details query
-type DetailField string
+// MangaRankingType is the type by which to retrieve manga ranking details
+type MangaRankingType string
 
-// SubFields attaches a sub query onto a DetailField
-func (df DetailField) SubFields(sq *DetailFields) DetailField {
-	str := sq.ToString()
-	return DetailField(fmt.Sprintf("%s{%s}", df, str))
-}
+// WatchStatus is the status of an anime on a user's list
+type WatchStatus string
 
-// DetailFields are a collection of DetailField
-type DetailFields []DetailField
+// ReadStatus is the status of a manga on a user's list
+type ReadStatus string
 
-// ToString converts a slice of DetailField into a comma separate string of fields
-func (d DetailFields) ToString() string {
-	var sb strings.Builder
-	for i, str := range d {
-		sb.WriteString(string(str))
-		if i < len(d)-1 {
-			sb.WriteString(",")
-		}
-	}
-	return sb.String()
-}
+// UserListSort is the value by which to sort user anime list results
+type UserListSort string
 
 // Common query values
 const (
-	LargeQueryLimit int = 500
-	SmallQueryLimit int = 100
+	UserListQueryLimit int = 1000
+	LargeQueryLimit    int = 500
+	SmallQueryLimit    int = 100
+
+	// UserMe is the user name that refers to the authenticated user
+	UserMe string = "@me"
 )
 
 // Season values specify the season in seasonal queries
@@ -131,69 +100,140 @@ func (rt RankingTypes) IsValid(str string) bool {
 	return false
 }
 
-// QueryField are the supported fields when performing a query
-// that results in a List or Ranking type response
+// MangaRankingType are the supported ways to query MAL manga rankings
+const (
+	MangaRankingAll          MangaRankingType = "all"
+	MangaRankingManga        MangaRankingType = "manga"
+	MangaRankingNovels       MangaRankingType = "novels"
+	MangaRankingOneShots     MangaRankingType = "oneshots"
+	MangaRankingDoujin       MangaRankingType = "doujin"
+	MangaRankingManhwa       MangaRankingType = "manhwa"
+	MangaRankingManhua       MangaRankingType = "manhua"
+	MangaRankingByPopularity MangaRankingType = "bypopularity"
+	MangaRankingFavorite     MangaRankingType = "favorite"
+)
+
+// MangaRankingTypes are a collection of MangaRankingType
+type MangaRankingTypes []MangaRankingType
+
+// IsValid checks to see if the supplied value is a valid MangaRankingType
+func (mrt MangaRankingTypes) IsValid(str string) bool {
+	converted := MangaRankingType(str)
+	for _, v := range mrt {
+		if v == converted {
+			return true
+		}
+	}
+	return false
+}
+
+// WatchStatus values specify the status of an anime on a user's list
+const (
+	WatchStatusWatching    WatchStatus = "watching"
+	WatchStatusCompleted   WatchStatus = "completed"
+	WatchStatusOnHold      WatchStatus = "on_hold"
+	WatchStatusDropped     WatchStatus = "dropped"
+	WatchStatusPlanToWatch WatchStatus = "plan_to_watch"
+)
+
+// WatchStatusTypes are a collection of WatchStatus
+type WatchStatusTypes []WatchStatus
+
+// IsValid checks to see if the supplied value is a valid WatchStatus
+func (wst WatchStatusTypes) IsValid(str string) bool {
+	converted := WatchStatus(str)
+	for _, v := range wst {
+		if v == converted {
+			return true
+		}
+	}
+	return false
+}
+
+// ReadStatus values specify the status of a manga on a user's list
 const (
-	FieldID                     QueryField = "id"
-	FieldTitle                  QueryField = "title"
-	FieldMainPicture            QueryField = "main_picture"
-	FieldAlternativeTitles      QueryField = "alternative_titles"
-	FieldStartDate              QueryField = "start_date"
-	FieldEndDate                QueryField = "end_date"
-	FieldSynopsis               QueryField = "synopsis"
-	FieldMean                   QueryField = "mean"
-	FieldRank                   QueryField = "rank"
-	FieldPopularity             QueryField = "popularity"
-	FieldNumListUsers           QueryField = "num_list_users"
-	FieldNumScoringUsers        QueryField = "num_scoring_users"
-	FieldNsfw                   QueryField = "nsfw"
-	FieldGenres                 QueryField = "genres"
-	FieldCreatedAt              QueryField = "created_at"
-	FieldUpdatedAt              QueryField = "updated_at"
-	FieldMediaType              QueryField = "media_type"
-	FieldStatus                 QueryField = "status"
-	FieldNumEpisodes            QueryField = "num_episodes"
-	FieldStartSeason            QueryField = "start_season"
-	FieldBroadcast              QueryField = "broadcast"
-	FieldSource                 QueryField = "source"
-	FieldAverageEpisodeDuration QueryField = "average_episode_duration"
-	FieldStudios                QueryField = "studios"
+	ReadStatusReading    ReadStatus = "reading"
+	ReadStatusCompleted  ReadStatus = "completed"
+	ReadStatusOnHold     ReadStatus = "on_hold"
+	ReadStatusDropped    ReadStatus = "dropped"
+	ReadStatusPlanToRead ReadStatus = "plan_to_read"
 )
 
-// DetailField are the supported fields when performing a query that
-// results in a Detail type response
+// ReadStatusTypes are a collection of ReadStatus
+type ReadStatusTypes []ReadStatus
+
+// IsValid checks to see if the supplied value is a valid ReadStatus
+func (rst ReadStatusTypes) IsValid(str string) bool {
+	converted := ReadStatus(str)
+	for _, v := range rst {
+		if v == converted {
+			return true
+		}
+	}
+	return false
+}
+
+// UserListSort specifies how to sort user anime list queries
 const (
-	DetailID                     DetailField = "id"
-	DetailTitle                  DetailField = "title"
-	DetailMainPicture            DetailField = "main_picture"
-	DetailAlternativeTitles      DetailField = "alternative_titles"
-	DetailStartDate              DetailField = "start_date"
-	DetailEndDate                DetailField = "end_date"
-	DetailSynopsis               DetailField = "synopsis"
-	DetailMean                   DetailField = "mean"
-	DetailRank                   DetailField = "rank"
-	DetailPopularity             DetailField = "popularity"
-	DetailNumListUsers           DetailField = "num_list_users"
-	DetailNumScoringUsers        DetailField = "num_scoring_users"
-	DetailNsfw                   DetailField = "nsfw"
-	DetailCreatedAt              DetailField = "created_at"
-	DetailUpdatedAt              DetailField = "updated_at"
-	DetailMediaType              DetailField = "media_type"
-	DetailStatus                 DetailField = "status"
-	DetailGenres                 DetailField = "genres"
-	DetailNumEpisodes            DetailField = "num_episodes"
-	DetailStartSeason            DetailField = "start_season"
-	DetailBroadcast              DetailField = "broadcast"
-	DetailSource                 DetailField = "source"
-	DetailAverageEpisodeDuration DetailField = "average_episode_duration"
-	DetailRating                 DetailField = "rating"
-	DetailPictures               DetailField = "pictures"
-	DetailBackground             DetailField = "background"
-	DetailRelatedAnime           DetailField = "related_anime"
-	DetailRelatedManga           DetailField = "related_manga"
-	DetailRecommendations        DetailField = "recommendations"
-	DetailStudios                DetailField = "studios"
-	DetailStatistics             DetailField = "statistics"
+	UserListSortScore     UserListSort = "list_score"
+	UserListSortUpdatedAt UserListSort = "list_updated_at"
+	UserListSortTitle     UserListSort = "anime_title"
+	UserListSortStartDate UserListSort = "anime_start_date"
+	UserListSortID        UserListSort = "anime_id"
+)
+
+// UserListSortTypes are a collection of UserListSort
+type UserListSortTypes []UserListSort
+
+// IsValid checks to see if the supplied value is a valid UserListSort
+func (ulst UserListSortTypes) IsValid(str string) bool {
+	converted := UserListSort(str)
+	for _, v := range ulst {
+		if v == converted {
+			return true
+		}
+	}
+	return false
+}
+
+// DetailField values are the names that fields were originally given for details queries. They are the same as
+// the matching Field values, and are kept for compatibility.
+const (
+	DetailID                     = FieldID
+	DetailTitle                  = FieldTitle
+	DetailMainPicture            = FieldMainPicture
+	DetailAlternativeTitles      = FieldAlternativeTitles
+	DetailStartDate              = FieldStartDate
+	DetailEndDate                = FieldEndDate
+	DetailSynopsis               = FieldSynopsis
+	DetailMean                   = FieldMean
+	DetailRank                   = FieldRank
+	DetailPopularity             = FieldPopularity
+	DetailNumListUsers           = FieldNumListUsers
+	DetailNumScoringUsers        = FieldNumScoringUsers
+	DetailNsfw                   = FieldNsfw
+	DetailCreatedAt              = FieldCreatedAt
+	DetailUpdatedAt              = FieldUpdatedAt
+	DetailMediaType              = FieldMediaType
+	DetailStatus                 = FieldStatus
+	DetailGenres                 = FieldGenres
+	DetailNumEpisodes            = FieldNumEpisodes
+	DetailStartSeason            = FieldStartSeason
+	DetailBroadcast              = FieldBroadcast
+	DetailSource                 = FieldSource
+	DetailAverageEpisodeDuration = FieldAverageEpisodeDuration
+	DetailRating                 = FieldRating
+	DetailPictures               = FieldPictures
+	DetailBackground             = FieldBackground
+	DetailRelatedAnime           = FieldRelatedAnime
+	DetailRelatedManga           = FieldRelatedManga
+	DetailRecommendations        = FieldRecommendations
+	DetailStudios                = FieldStudios
+	DetailStatistics             = FieldStatistics
+	DetailNumVolumes             = FieldNumVolumes
+	DetailNumChapters            = FieldNumChapters
+	DetailAuthors                = FieldAuthors
+	DetailSerialization          = FieldSerialization
 )
 
 // Common QueryFields when running general queries
@@ -260,4 +300,44 @@ var (
 		RankingByPopularity,
 		RankingFavorite,
 	}
+
+	// MangaRankTypeQueries are the supported query values you can use when querying for manga rankings
+	MangaRankTypeQueries MangaRankingTypes = []MangaRankingType{
+		MangaRankingAll,
+		MangaRankingManga,
+		MangaRankingNovels,
+		MangaRankingOneShots,
+		MangaRankingDoujin,
+		MangaRankingManhwa,
+		MangaRankingManhua,
+		MangaRankingByPopularity,
+		MangaRankingFavorite,
+	}
+
+	// WatchStatusQueries are the supported query values used to filter user anime lists
+	WatchStatusQueries WatchStatusTypes = []WatchStatus{
+		WatchStatusWatching,
+		WatchStatusCompleted,
+		WatchStatusOnHold,
+		WatchStatusDropped,
+		WatchStatusPlanToWatch,
+	}
+
+	// ReadStatusQueries are the supported values for the status of a manga on a user's list
+	ReadStatusQueries ReadStatusTypes = []ReadStatus{
+		ReadStatusReading,
+		ReadStatusCompleted,
+		ReadStatusOnHold,
+		ReadStatusDropped,
+		ReadStatusPlanToRead,
+	}
+
+	// UserListSortQueries are the supported query values used when sorting user anime lists
+	UserListSortQueries UserListSortTypes = []UserListSort{
+		UserListSortScore,
+		UserListSortUpdatedAt,
+		UserListSortTitle,
+		UserListSortStartDate,
+		UserListSortID,
+	}
 )
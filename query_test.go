@@ -77,3 +77,49 @@ func TestDetailSubFields(t *testing.T) {
 		})
 	}
 }
+
+func TestQuerySubFields(t *testing.T) {
+	testCases := []struct {
+		root     QueryField
+		in       *QueryFields
+		expected string
+	}{
+		{FieldAlternativeTitles, &QueryFields{"en"}, "alternative_titles{en}"},
+		{FieldMyListStatus, &QueryFields{"status", "score"}, "my_list_status{status,score}"},
+		{FieldRelatedAnime, &QueryFields{
+			FieldRank,
+			FieldAlternativeTitles.SubFields(&QueryFields{"en", "ja"}),
+			FieldRecommendations.SubFields(&QueryFields{
+				FieldMainPicture.SubFields(&QueryFields{"medium"}),
+			}),
+		}, "related_anime{rank,alternative_titles{en,ja},recommendations{main_picture{medium}}}"},
+		{FieldTitle, &QueryFields{}, "title"},
+		{FieldTitle, nil, "title"},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("Test case %d", i), func(t *testing.T) {
+			if got := tc.root.SubFields(tc.in); got != QueryField(tc.expected) {
+				t.Errorf("Expected %s, got %s", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestMangaRankingTypeIsValid(t *testing.T) {
+	testCases := []struct {
+		in       string
+		expected bool
+	}{
+		{"tv", false},
+		{"manhwa", true},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("Test case %d", i), func(t *testing.T) {
+			if got := MangaRankTypeQueries.IsValid(tc.in); got != tc.expected {
+				t.Errorf("Expected %t, got %t", tc.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package malgomate
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// RateLimiter throttles outgoing requests. The Client calls Wait before every request attempt (retries
+// included), and gives up on the request if Wait returns an error.
+type RateLimiter interface {
+	Wait(ctx context.Context) error
+}
+
+// TokenBucket is a RateLimiter that allows requests at a steady rate, with bursts of up to a set number of
+// requests. It is safe to share a single TokenBucket between multiple clients.
+type TokenBucket struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// NewTokenBucket is a constructor for a TokenBucket that refills at rps tokens per second and holds at most
+// burst tokens. The bucket starts out full. A rate of zero or less disables throttling.
+func NewTokenBucket(rps float64, burst int) *TokenBucket {
+	if burst < 1 {
+		burst = 1
+	}
+	return &TokenBucket{
+		rate:   rps,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// Wait blocks until a token is available or the context is done. Callers are served in the order they
+// arrive, as each call reserves its token up front.
+func (tb *TokenBucket) Wait(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if tb.rate <= 0 {
+		return nil
+	}
+
+	tb.mu.Lock()
+	now := time.Now()
+	tb.tokens += now.Sub(tb.last).Seconds() * tb.rate
+	if tb.tokens > tb.burst {
+		tb.tokens = tb.burst
+	}
+	tb.last = now
+	tb.tokens--
+	var wait time.Duration
+	if tb.tokens < 0 {
+		wait = time.Duration(-tb.tokens / tb.rate * float64(time.Second))
+	}
+	tb.mu.Unlock()
+
+	if err := sleep(ctx, wait); err != nil {
+		// Hand the reserved token back so that cancelled callers don't slow down everyone else
+		tb.mu.Lock()
+		tb.tokens++
+		tb.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+// sharedLimiters holds the limiters handed out by SharedRateLimiter, keyed by client ID
+var sharedLimiters = struct {
+	sync.Mutex
+	m map[string]*TokenBucket
+}{m: map[string]*TokenBucket{}}
+
+// SharedRateLimiter returns the TokenBucket registered for the supplied MAL client ID, creating it with the given
+// rate and burst the first time the client ID is seen. Every Client that uses the same client ID can then share
+// a single limit. Later calls for an existing client ID return the original limiter unchanged.
+func SharedRateLimiter(clientID string, rps float64, burst int) *TokenBucket {
+	sharedLimiters.Lock()
+	defer sharedLimiters.Unlock()
+	if tb, ok := sharedLimiters.m[clientID]; ok {
+		return tb
+	}
+	tb := NewTokenBucket(rps, burst)
+	sharedLimiters.m[clientID] = tb
+	return tb
+}
@@ -0,0 +1,102 @@
+package malgomate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestTokenBucket(t *testing.T) {
+	testCases := []struct {
+		rps     float64
+		burst   int
+		calls   int
+		minTime time.Duration
+		maxTime time.Duration
+	}{
+		{0, 1, 10, 0, 50 * time.Millisecond},
+		{10, 5, 5, 0, 50 * time.Millisecond},
+		{20, 2, 4, 90 * time.Millisecond, 500 * time.Millisecond},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("Test case %d", i), func(t *testing.T) {
+			tb := NewTokenBucket(tc.rps, tc.burst)
+			start := time.Now()
+			for n := 0; n < tc.calls; n++ {
+				if err := tb.Wait(context.Background()); err != nil {
+					t.Fatalf("Unexpected error: %q", err)
+				}
+			}
+			if took := time.Since(start); took < tc.minTime || took > tc.maxTime {
+				t.Errorf("Expected %d calls to take between %s and %s, took %s", tc.calls, tc.minTime, tc.maxTime, took)
+			}
+		})
+	}
+}
+
+func TestTokenBucketCancellation(t *testing.T) {
+	tb := NewTokenBucket(0.1, 1)
+	if err := tb.Wait(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %q", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := tb.Wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected context.DeadlineExceeded, got %v", err)
+	}
+	if tb.tokens < -0.01 {
+		t.Errorf("Expected the cancelled reservation to be returned, tokens at %f", tb.tokens)
+	}
+}
+
+func TestSharedRateLimiter(t *testing.T) {
+	a := SharedRateLimiter("shared-id", 1, 1)
+	b := SharedRateLimiter("shared-id", 50, 50)
+	other := SharedRateLimiter("other-id", 1, 1)
+	if a != b {
+		t.Errorf("Expected the same limiter for the same client ID")
+	}
+	if a == other {
+		t.Errorf("Expected different limiters for different client IDs")
+	}
+}
+
+// countingLimiter records calls to Wait, and fails once the configured number of calls is reached
+type countingLimiter struct {
+	calls   int
+	failsAt int
+}
+
+func (cl *countingLimiter) Wait(ctx context.Context) error {
+	cl.calls++
+	if cl.calls == cl.failsAt {
+		return errors.New("limited")
+	}
+	return nil
+}
+
+func TestClientLimiter(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	limiter := &countingLimiter{}
+	c.Limiter = limiter
+	c.Retry = &RetryPolicy{MaxAttempts: 3, RetryableStatusCodes: []int{http.StatusServiceUnavailable}}
+
+	if _, err := c.GetDetails(&DetailsQuery{Id: 1}); !errors.Is(err, ErrServerError) {
+		t.Fatalf("Expected a server error, got %v", err)
+	}
+	if limiter.calls != 3 {
+		t.Errorf("Expected the limiter to be waited on for every attempt, got %d calls", limiter.calls)
+	}
+
+	limiter.calls, limiter.failsAt = 0, 1
+	if _, err := c.GetDetails(&DetailsQuery{Id: 1}); err == nil || err.Error() != "limited" {
+		t.Errorf("Expected the limiter error to be returned, got %v", err)
+	}
+}
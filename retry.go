@@ -0,0 +1,145 @@
+package malgomate
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy controls how the Client retries failed requests. Only GET requests are ever retried. Attach a
+// policy to the Client through its Retry field; a nil policy means that every request is only attempted once.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts made for a request, including the first one
+	MaxAttempts int
+	// BaseDelay is how long to wait before the first retry. The delay doubles on every following attempt.
+	BaseDelay time.Duration
+	// MaxDelay caps the computed backoff delay. Zero means no cap.
+	MaxDelay time.Duration
+	// Jitter is the fraction (0 to 1) of each delay that is randomized, to keep clients from retrying in lockstep
+	Jitter float64
+	// RetryableStatusCodes are the response status codes that should be retried
+	RetryableStatusCodes []int
+	// RetryNetworkErrors retries requests that failed before a response was received (timeouts, resets, etc.)
+	RetryNetworkErrors bool
+	// ShouldRetry, when set, replaces the status code and network error checks for deciding if an error is retryable
+	ShouldRetry func(err error) bool
+	// OnRetry, when set, is called before waiting on each retry
+	OnRetry func(RetryAttempt)
+}
+
+// RetryAttempt describes a failed attempt that is about to be retried
+type RetryAttempt struct {
+	// Attempt is the number of the attempt that failed, starting at 1
+	Attempt int
+	// Err is the error returned by the failed attempt
+	Err error
+	// Delay is how long the client will wait before the next attempt
+	Delay time.Duration
+	// Request is the request being retried
+	Request *http.Request
+}
+
+// DefaultRetryPolicy is a constructor for a RetryPolicy with some sane default values. It makes up to 4 attempts,
+// backing off from 500ms up to 10s, and retries rate limiting, server errors and network errors.
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts: 4,
+		BaseDelay:   500 * time.Millisecond,
+		MaxDelay:    10 * time.Second,
+		Jitter:      0.2,
+		RetryableStatusCodes: []int{
+			http.StatusTooManyRequests,
+			http.StatusInternalServerError,
+			http.StatusBadGateway,
+			http.StatusServiceUnavailable,
+			http.StatusGatewayTimeout,
+		},
+		RetryNetworkErrors: true,
+	}
+}
+
+// retryable checks to see if the supplied error should be retried under the policy
+func (rp *RetryPolicy) retryable(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	if rp.ShouldRetry != nil {
+		return rp.ShouldRetry(err)
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		for _, code := range rp.RetryableStatusCodes {
+			if code == apiErr.StatusCode {
+				return true
+			}
+		}
+		return false
+	}
+
+	return rp.RetryNetworkErrors
+}
+
+// delay works out how long to wait after the given failed attempt. A Retry-After header sent back by MAL
+// always takes priority over the computed backoff.
+func (rp *RetryPolicy) delay(attempt int, err error) time.Duration {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		if d, ok := parseRetryAfter(apiErr.Header.Get("Retry-After")); ok {
+			return d
+		}
+	}
+
+	d := rp.BaseDelay
+	for i := 1; i < attempt && d < math.MaxInt64/2; i++ {
+		if rp.MaxDelay > 0 && d >= rp.MaxDelay {
+			break
+		}
+		d *= 2
+	}
+	if rp.MaxDelay > 0 && d > rp.MaxDelay {
+		d = rp.MaxDelay
+	}
+	if rp.Jitter > 0 && d > 0 {
+		spread := float64(d) * rp.Jitter
+		d = d - time.Duration(spread) + time.Duration(rand.Float64()*2*spread)
+	}
+	return d
+}
+
+// parseRetryAfter handles both forms of the Retry-After header, a number of seconds or an HTTP date
+func parseRetryAfter(v string) (time.Duration, bool) {
+	if v == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(v); err == nil && secs >= 0 {
+		return time.Duration(secs) * time.Second, true
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		d := time.Until(t)
+		if d < 0 {
+			d = 0
+		}
+		return d, true
+	}
+	return 0, false
+}
+
+// sleep waits for the specified duration, returning early if the context is done
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
@@ -0,0 +1,110 @@
+package malgomate
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRetry(t *testing.T) {
+	testCases := []struct {
+		failures int
+		status   int
+		attempts int32
+		success  bool
+	}{
+		{0, http.StatusServiceUnavailable, 1, true},
+		{2, http.StatusServiceUnavailable, 3, true},
+		{2, http.StatusTooManyRequests, 3, true},
+		{5, http.StatusBadGateway, 3, false},
+		{1, http.StatusNotFound, 1, false},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("Test case %d", i), func(t *testing.T) {
+			var calls int32
+			c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				if int(atomic.AddInt32(&calls, 1)) <= tc.failures {
+					w.Header().Set("Retry-After", "0")
+					w.WriteHeader(tc.status)
+					return
+				}
+				fmt.Fprint(w, `{"id":1,"title":"Test"}`)
+			})
+
+			var retries []RetryAttempt
+			c.Retry = DefaultRetryPolicy()
+			c.Retry.MaxAttempts = 3
+			c.Retry.OnRetry = func(ra RetryAttempt) { retries = append(retries, ra) }
+
+			_, err := c.GetDetails(&DetailsQuery{Id: 1})
+			if (err == nil) != tc.success {
+				t.Fatalf("Expected success to be %t, got error %v", tc.success, err)
+			}
+			if calls != tc.attempts {
+				t.Errorf("Expected %d attempts, got %d", tc.attempts, calls)
+			}
+			if len(retries) != int(tc.attempts)-1 {
+				t.Errorf("Expected %d retry hooks, got %d", tc.attempts-1, len(retries))
+			}
+			for n, ra := range retries {
+				if ra.Attempt != n+1 || ra.Delay != 0 || ra.Err == nil {
+					t.Errorf("Unexpected retry attempt %+v", ra)
+				}
+			}
+		})
+	}
+}
+
+func TestRetryNetworkErrors(t *testing.T) {
+	c := NewClient("test-key")
+	c.BaseURL = "http://127.0.0.1:1"
+	c.Retry = &RetryPolicy{MaxAttempts: 3, RetryNetworkErrors: false}
+
+	var retried bool
+	c.Retry.OnRetry = func(RetryAttempt) { retried = true }
+	if _, err := c.GetDetails(&DetailsQuery{Id: 1}); err == nil || retried {
+		t.Fatalf("Expected a single failed attempt, got err %v and retried %t", err, retried)
+	}
+
+	c.Retry.RetryNetworkErrors = true
+	var attempts int
+	c.Retry.OnRetry = func(RetryAttempt) { attempts++ }
+	_, err := c.GetDetails(&DetailsQuery{Id: 1})
+	var apiErr *APIError
+	if err == nil || errors.As(err, &apiErr) || attempts != 2 {
+		t.Errorf("Expected network error after 2 retries, got err %v and %d retries", err, attempts)
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	rp := &RetryPolicy{BaseDelay: 100 * time.Millisecond, MaxDelay: 300 * time.Millisecond}
+	testCases := []struct {
+		attempt    int
+		retryAfter string
+		expected   time.Duration
+	}{
+		{1, "", 100 * time.Millisecond},
+		{2, "", 200 * time.Millisecond},
+		{3, "", 300 * time.Millisecond},
+		{40, "", 300 * time.Millisecond},
+		{1, "7", 7 * time.Second},
+		{1, "Mon, 02 Jan 2006 15:04:05 GMT", 0},
+		{1, "soon", 100 * time.Millisecond},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("Test case %d", i), func(t *testing.T) {
+			err := &APIError{StatusCode: http.StatusTooManyRequests, Header: http.Header{}}
+			if tc.retryAfter != "" {
+				err.Header.Set("Retry-After", tc.retryAfter)
+			}
+			if got := rp.delay(tc.attempt, err); got != tc.expected {
+				t.Errorf("Expected %s, got %s", tc.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package malgomate
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// User is a MAL user profile
+type User struct {
+	ID              int              `json:"id"`
+	Name            string           `json:"name"`
+	Picture         string           `json:"picture,omitempty"`
+	Gender          string           `json:"gender,omitempty"`
+	Birthday        string           `json:"birthday,omitempty"`
+	Location        string           `json:"location,omitempty"`
+	JoinedAt        *Timestamp       `json:"joined_at,omitempty"`
+	TimeZone        string           `json:"time_zone,omitempty"`
+	IsSupporter     bool             `json:"is_supporter,omitempty"`
+	AnimeStatistics *AnimeStatistics `json:"anime_statistics,omitempty"`
+}
+
+// JSON is a helper function that converts a user object to a JSON string
+func (u *User) JSON() (string, error) {
+	if s, err := json.MarshalIndent(u, "", "  "); err == nil {
+		return string(s), err
+	} else {
+		return "", err
+	}
+}
+
+// AnimeStatistics are the totals across a user's anime list
+type AnimeStatistics struct {
+	NumItemsWatching    int     `json:"num_items_watching"`
+	NumItemsCompleted   int     `json:"num_items_completed"`
+	NumItemsOnHold      int     `json:"num_items_on_hold"`
+	NumItemsDropped     int     `json:"num_items_dropped"`
+	NumItemsPlanToWatch int     `json:"num_items_plan_to_watch"`
+	NumItems            int     `json:"num_items"`
+	NumDaysWatched      float64 `json:"num_days_watched"`
+	NumDaysWatching     float64 `json:"num_days_watching"`
+	NumDaysCompleted    float64 `json:"num_days_completed"`
+	NumDaysOnHold       float64 `json:"num_days_on_hold"`
+	NumDaysDropped      float64 `json:"num_days_dropped"`
+	NumDays             float64 `json:"num_days"`
+	NumEpisodes         int     `json:"num_episodes"`
+	NumTimesRewatched   int     `json:"num_times_rewatched"`
+	MeanScore           float64 `json:"mean_score"`
+}
+
+// NumItemsByStatus is a helper function that groups the item counts by their WatchStatus
+func (as *AnimeStatistics) NumItemsByStatus() map[WatchStatus]int {
+	return map[WatchStatus]int{
+		WatchStatusWatching:    as.NumItemsWatching,
+		WatchStatusCompleted:   as.NumItemsCompleted,
+		WatchStatusOnHold:      as.NumItemsOnHold,
+		WatchStatusDropped:     as.NumItemsDropped,
+		WatchStatusPlanToWatch: as.NumItemsPlanToWatch,
+	}
+}
+
+// UserInfoQuery is used to query the profile of the authenticated user. Set AnimeStatistics to also
+// retrieve the totals across the user's anime list.
+type UserInfoQuery struct {
+	AnimeStatistics bool
+}
+
+// GetMyUserInfo retrieves the profile of the authenticated user. Requires a client created with NewAuthClient,
+// and returns ErrAuthRequired otherwise.
+func (c *Client) GetMyUserInfo(q *UserInfoQuery) (*User, error) {
+	return c.GetMyUserInfoContext(context.Background(), q)
+}
+
+// GetMyUserInfoContext is the same as GetMyUserInfo, but the request is bound to the provided context. Cancelling
+// the context aborts the request.
+func (c *Client) GetMyUserInfoContext(ctx context.Context, q *UserInfoQuery) (*User, error) {
+	// Check for required values
+	if c.TokenSource == nil {
+		return nil, ErrAuthRequired
+	}
+
+	queryString := fmt.Sprintf("%s/users/%s", c.BaseURL, UserMe)
+	if q != nil && q.AnimeStatistics {
+		queryString += "?fields=anime_statistics"
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, queryString, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res := User{}
+	if err := c.sendRequest(req, &res); err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
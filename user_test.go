@@ -0,0 +1,60 @@
+package malgomate
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/fuzzylimes/malgomate/auth"
+)
+
+func TestGetMyUserInfo(t *testing.T) {
+	testCases := []struct {
+		in    *UserInfoQuery
+		query string
+	}{
+		{nil, ""},
+		{&UserInfoQuery{}, ""},
+		{&UserInfoQuery{AnimeStatistics: true}, "fields=anime_statistics"},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("Test case %d", i), func(t *testing.T) {
+			c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/users/@me" || r.URL.RawQuery != tc.query {
+					t.Errorf("Unexpected request %q", r.URL)
+				}
+				fmt.Fprint(w, `{
+					"id": 1234,
+					"name": "someone",
+					"location": "",
+					"joined_at": "2014-01-02T03:04:05+00:00",
+					"time_zone": "Asia/Tokyo",
+					"is_supporter": true,
+					"anime_statistics": {"num_items_watching": 2, "num_items_completed": 10, "num_items": 12, "num_days_watched": 4.5, "mean_score": 7.25}
+				}`)
+			})
+			c.TokenSource = auth.StaticTokenSource(&auth.Token{AccessToken: "abc"})
+
+			res, err := c.GetMyUserInfo(tc.in)
+			if err != nil {
+				t.Fatalf("Unexpected error: %q", err)
+			}
+			if res.ID != 1234 || res.TimeZone != "Asia/Tokyo" || !res.IsSupporter || res.JoinedAt == nil || res.JoinedAt.Year() != 2014 {
+				t.Errorf("Unexpected user %+v", res)
+			}
+			stats := res.AnimeStatistics
+			if stats == nil || stats.NumDaysWatched != 4.5 || stats.MeanScore != 7.25 || stats.NumItemsByStatus()[WatchStatusCompleted] != 10 {
+				t.Errorf("Unexpected statistics %+v", stats)
+			}
+		})
+	}
+}
+
+func TestGetMyUserInfoRequiresAuth(t *testing.T) {
+	c := NewClient("test-key")
+	if _, err := c.GetMyUserInfo(&UserInfoQuery{}); !errors.Is(err, ErrAuthRequired) {
+		t.Errorf("Expected ErrAuthRequired, got %v", err)
+	}
+}
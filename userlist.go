@@ -0,0 +1,244 @@
+package malgomate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// UserAnimeListing is a wrapper object for anime objects on a user's list, along with the user's status for
+// that anime
+type UserAnimeListing struct {
+	Node       Anime       `json:"node"`
+	ListStatus *ListStatus `json:"list_status,omitempty"`
+}
+
+// ListStatus is the state of an anime on a user's list
+type ListStatus struct {
+	Status             WatchStatus  `json:"status,omitempty"`
+	Score              int          `json:"score"`
+	NumEpisodesWatched int          `json:"num_episodes_watched"`
+	IsRewatching       bool         `json:"is_rewatching"`
+	UpdatedAt          *Timestamp   `json:"updated_at,omitempty"`
+	StartDate          *PartialDate `json:"start_date,omitempty"`
+	FinishDate         *PartialDate `json:"finish_date,omitempty"`
+	Priority           int          `json:"priority,omitempty"`
+	NumTimesRewatched  int          `json:"num_times_rewatched,omitempty"`
+	RewatchValue       int          `json:"rewatch_value,omitempty"`
+	Tags               []string     `json:"tags,omitempty"`
+	Comments           string       `json:"comments,omitempty"`
+}
+
+// UserAnimeListQuery is used to query the anime list of a MAL user. Supports fields of the QueryField type.
+// The UserName may be set to UserMe to query the list of the authenticated user.
+type UserAnimeListQuery struct {
+	UserName string
+	Status   WatchStatus
+	Sort     UserListSort
+	Limit    int
+	Offset   int
+	Fields   QueryFields
+}
+
+// GetUserAnimeList queries the anime list of a MAL user. These queries return a paged list of responses containing
+// the fields specified in the initial request object, along with the user's list status for each anime. Querying
+// the list of UserMe requires a client created with NewAuthClient. If not included, the following default values
+// will be used:
+//   - UserName - "@me"
+//   - Status - all statuses
+//   - Limit - 100 (max 1000)
+//   - Offset - 0
+//   - Fields - "id,title,main_picture,list_status"
+func (c *Client) GetUserAnimeList(q *UserAnimeListQuery) (*UserAnimeListPage, error) {
+	return c.GetUserAnimeListContext(context.Background(), q)
+}
+
+// GetUserAnimeListContext is the same as GetUserAnimeList, but the request is bound to the provided context.
+// Cancelling the context aborts the request.
+func (c *Client) GetUserAnimeListContext(ctx context.Context, q *UserAnimeListQuery) (*UserAnimeListPage, error) {
+	req, err := c.userAnimeListRequest(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	res := UserAnimeListPage{}
+	if err := c.sendRequest(req, &res); err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+// userAnimeListRequest builds the request for a user anime list query, filling in any defaults and checking the
+// query is valid
+func (c *Client) userAnimeListRequest(ctx context.Context, q *UserAnimeListQuery) (*http.Request, error) {
+	// Handle defaults
+	if q.UserName == "" {
+		q.UserName = UserMe
+	}
+	if q.UserName == UserMe && c.TokenSource == nil {
+		return nil, ErrAuthRequired
+	}
+	if q.Limit == 0 {
+		q.Limit = 100
+	} else if q.Limit > UserListQueryLimit {
+		q.Limit = UserListQueryLimit
+	}
+	if len(q.Fields) == 0 {
+		q.Fields = BasicFieldQuery
+	}
+	// The list status is only returned when asked for, and is the whole point of the query
+	fields := q.Fields
+	if !fields.contains(FieldListStatus) {
+		fields = append(fields[:len(fields):len(fields)], FieldListStatus)
+	}
+	if err := fields.validate(EndpointUserList, mediaAnime); err != nil {
+		return nil, err
+	}
+
+	queryString := fmt.Sprintf("%s/users/%s/animelist?limit=%d&offset=%d&fields=%s", c.BaseURL, url.PathEscape(q.UserName), q.Limit, q.Offset, fields.ToString())
+	if q.Status != "" {
+		queryString += fmt.Sprintf("&status=%s", q.Status)
+	}
+	if q.Sort != "" {
+		queryString += fmt.Sprintf("&sort=%s", q.Sort)
+	}
+	return http.NewRequestWithContext(ctx, http.MethodGet, queryString, nil)
+}
+
+// ListStatusUpdate holds the changes to make to an anime on the authenticated user's list. Only the values that
+// are set are sent to MAL; use the Int, Bool and String helpers to set the pointer fields.
+type ListStatusUpdate struct {
+	Status             WatchStatus
+	IsRewatching       *bool
+	Score              *int
+	NumWatchedEpisodes *int
+	Priority           *int
+	NumTimesRewatched  *int
+	RewatchValue       *int
+	Tags               []string
+	Comments           *string
+}
+
+// values converts the update into form values, skipping anything that isn't set
+func (u *ListStatusUpdate) values() url.Values {
+	v := url.Values{}
+	if u.Status != "" {
+		v.Set("status", string(u.Status))
+	}
+	if u.IsRewatching != nil {
+		v.Set("is_rewatching", strconv.FormatBool(*u.IsRewatching))
+	}
+	ints := []struct {
+		key string
+		val *int
+	}{
+		{"score", u.Score},
+		{"num_watched_episodes", u.NumWatchedEpisodes},
+		{"priority", u.Priority},
+		{"num_times_rewatched", u.NumTimesRewatched},
+		{"rewatch_value", u.RewatchValue},
+	}
+	for _, i := range ints {
+		if i.val != nil {
+			v.Set(i.key, strconv.Itoa(*i.val))
+		}
+	}
+	if u.Tags != nil {
+		v.Set("tags", strings.Join(u.Tags, ","))
+	}
+	if u.Comments != nil {
+		v.Set("comments", *u.Comments)
+	}
+	return v
+}
+
+// Int is a helper function that returns a pointer to the supplied int
+func Int(v int) *int { return &v }
+
+// Bool is a helper function that returns a pointer to the supplied bool
+func Bool(v bool) *bool { return &v }
+
+// String is a helper function that returns a pointer to the supplied string
+func String(v string) *string { return &v }
+
+// UpdateMyListStatus adds an anime to the authenticated user's list, or updates it if it is already there. Only
+// the values set on the update are changed. Returns the resulting list status. Requires a client created with
+// NewAuthClient.
+func (c *Client) UpdateMyListStatus(id int, update *ListStatusUpdate) (*ListStatus, error) {
+	return c.UpdateMyListStatusContext(context.Background(), id, update)
+}
+
+// UpdateMyListStatusContext is the same as UpdateMyListStatus, but the request is bound to the provided context.
+// Cancelling the context aborts the request.
+func (c *Client) UpdateMyListStatusContext(ctx context.Context, id int, update *ListStatusUpdate) (*ListStatus, error) {
+	// Check for required values
+	if id == 0 {
+		return nil, errors.New("missing required parameter: id must be set")
+	}
+	if c.TokenSource == nil {
+		return nil, ErrAuthRequired
+	}
+	if update == nil {
+		update = &ListStatusUpdate{}
+	}
+
+	queryString := fmt.Sprintf("%s/anime/%d/my_list_status", c.BaseURL, id)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPatch, queryString, strings.NewReader(update.values().Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	res := ListStatus{}
+	if err := c.sendRequest(req, &res); err != nil {
+		return nil, err
+	}
+	if c.Cache != nil {
+		c.invalidateListWrite(id)
+	}
+
+	return &res, nil
+}
+
+// DeleteMyListItem removes an anime from the authenticated user's list. MAL responds with a 404 when the anime
+// is not on the list, which is reported as ErrNotInList so that it can be told apart from other failures (and
+// safely ignored when retrying a delete). The *APIError is still available through errors.As. Requires a client
+// created with NewAuthClient.
+func (c *Client) DeleteMyListItem(id int) error {
+	return c.DeleteMyListItemContext(context.Background(), id)
+}
+
+// DeleteMyListItemContext is the same as DeleteMyListItem, but the request is bound to the provided context.
+// Cancelling the context aborts the request.
+func (c *Client) DeleteMyListItemContext(ctx context.Context, id int) error {
+	// Check for required values
+	if id == 0 {
+		return errors.New("missing required parameter: id must be set")
+	}
+	if c.TokenSource == nil {
+		return ErrAuthRequired
+	}
+
+	queryString := fmt.Sprintf("%s/anime/%d/my_list_status", c.BaseURL, id)
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, queryString, nil)
+	if err != nil {
+		return err
+	}
+
+	if err := c.sendRequest(req, nil); err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return &notInListError{id: id, err: err}
+		}
+		return err
+	}
+	if c.Cache != nil {
+		c.invalidateListWrite(id)
+	}
+
+	return nil
+}
@@ -0,0 +1,158 @@
+package malgomate
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/fuzzylimes/malgomate/auth"
+)
+
+func TestGetUserAnimeList(t *testing.T) {
+	var base string
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/users/some_user/animelist" {
+			t.Errorf("Unexpected path %q", r.URL.Path)
+		}
+		q := r.URL.Query()
+		if q.Get("status") != "watching" || q.Get("sort") != "list_score" || q.Get("fields") != "id,title,list_status" || q.Get("limit") != "1000" {
+			t.Errorf("Unexpected query %q", r.URL.RawQuery)
+		}
+		fmt.Fprintf(w, `{
+			"data": [{
+				"node": {"id": 1, "title": "Cowboy Bebop"},
+				"list_status": {"status": "watching", "score": 9, "num_episodes_watched": 12, "is_rewatching": true, "updated_at": "2022-01-02T03:04:05+00:00"}
+			}],
+			"paging": {"next": "%s/users/some_user/animelist?offset=1"}
+		}`, base)
+	})
+	base = c.BaseURL
+
+	res, err := c.GetUserAnimeList(&UserAnimeListQuery{
+		UserName: "some_user",
+		Status:   WatchStatusWatching,
+		Sort:     UserListSortScore,
+		Limit:    5000,
+		Fields:   QueryFields{FieldID, FieldTitle},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %q", err)
+	}
+
+	if len(res.Data) != 1 {
+		t.Fatalf("Expected 1 listing, got %d", len(res.Data))
+	}
+	l := res.Data[0]
+	if l.Node.Title != "Cowboy Bebop" || l.ListStatus == nil {
+		t.Fatalf("Unexpected listing %+v", l)
+	}
+	updatedAt := time.Date(2022, 1, 2, 3, 4, 5, 0, time.UTC)
+	if l.ListStatus.UpdatedAt == nil || !l.ListStatus.UpdatedAt.Equal(updatedAt) {
+		t.Errorf("Expected updated at %s, got %v", updatedAt, l.ListStatus.UpdatedAt)
+	}
+	l.ListStatus.UpdatedAt = nil
+	expected := ListStatus{Status: WatchStatusWatching, Score: 9, NumEpisodesWatched: 12, IsRewatching: true}
+	if fmt.Sprint(*l.ListStatus) != fmt.Sprint(expected) {
+		t.Errorf("Expected %+v, got %+v", expected, *l.ListStatus)
+	}
+	if !res.Paging.HasNext() {
+		t.Errorf("Expected a next page")
+	}
+}
+
+func TestGetUserAnimeListMe(t *testing.T) {
+	testCases := []struct {
+		ts  auth.TokenSource
+		err error
+	}{
+		{nil, ErrAuthRequired},
+		{auth.StaticTokenSource(&auth.Token{AccessToken: "abc"}), nil},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("Test case %d", i), func(t *testing.T) {
+			c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/users/@me/animelist" {
+					t.Errorf("Unexpected path %q", r.URL.Path)
+				}
+				fmt.Fprint(w, `{"data":[],"paging":{}}`)
+			})
+			c.TokenSource = tc.ts
+
+			if _, err := c.GetUserAnimeList(&UserAnimeListQuery{}); !errors.Is(err, tc.err) {
+				t.Errorf("Expected %v, got %v", tc.err, err)
+			}
+		})
+	}
+}
+
+func TestUpdateMyListStatus(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPatch || r.URL.Path != "/anime/21/my_list_status" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		if ct := r.Header.Get("Content-Type"); ct != "application/x-www-form-urlencoded" {
+			t.Errorf("Unexpected content type %q", ct)
+		}
+		r.ParseForm()
+		expected := "comments=great+show&is_rewatching=false&num_watched_episodes=3&score=0&status=watching&tags=a%2Cb"
+		if got := r.PostForm.Encode(); got != expected {
+			t.Errorf("Expected form %q, got %q", expected, got)
+		}
+		fmt.Fprint(w, `{"status":"watching","score":0,"num_episodes_watched":3,"is_rewatching":false,"tags":["a","b"],"comments":"great show"}`)
+	})
+	c.TokenSource = auth.StaticTokenSource(&auth.Token{AccessToken: "abc"})
+
+	res, err := c.UpdateMyListStatus(21, &ListStatusUpdate{
+		Status:             WatchStatusWatching,
+		IsRewatching:       Bool(false),
+		Score:              Int(0),
+		NumWatchedEpisodes: Int(3),
+		Tags:               []string{"a", "b"},
+		Comments:           String("great show"),
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %q", err)
+	}
+	if res.Status != WatchStatusWatching || res.NumEpisodesWatched != 3 || len(res.Tags) != 2 || res.Comments != "great show" {
+		t.Errorf("Unexpected list status %+v", res)
+	}
+}
+
+func TestDeleteMyListItem(t *testing.T) {
+	testCases := []struct {
+		status int
+		auth   bool
+		err    error
+	}{
+		{http.StatusOK, true, nil},
+		{http.StatusNotFound, true, ErrNotInList},
+		{http.StatusOK, false, ErrAuthRequired},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("Test case %d", i), func(t *testing.T) {
+			c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodDelete || r.URL.Path != "/anime/21/my_list_status" {
+					t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+				}
+				w.WriteHeader(tc.status)
+				fmt.Fprint(w, `[]`)
+			})
+			if tc.auth {
+				c.TokenSource = auth.StaticTokenSource(&auth.Token{AccessToken: "abc"})
+			}
+
+			err := c.DeleteMyListItem(21)
+			if !errors.Is(err, tc.err) {
+				t.Errorf("Expected %v, got %v", tc.err, err)
+			}
+			var apiErr *APIError
+			if tc.status != http.StatusOK && (!errors.As(err, &apiErr) || apiErr.StatusCode != tc.status) {
+				t.Errorf("Expected the *APIError to be kept, got %v", err)
+			}
+		})
+	}
+}